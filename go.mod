@@ -3,6 +3,6 @@ module github.com/AJRDRGZ/db-query-builder
 go 1.16
 
 require (
-	github.com/lib/pq v1.10.7 // indirect
-	github.com/stretchr/testify v1.7.0 // indirect
+	github.com/lib/pq v1.10.7
+	github.com/stretchr/testify v1.7.0
 )
@@ -0,0 +1,45 @@
+package httphandler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+)
+
+// PaginationFromRequest returns the Pagination from the query params `page` and `limit`
+// of the request. The missing page is 1, the missing limit is defaultLimit, and the limit
+// greater than maxLimit is clamped to maxLimit.
+// It returns models.ErrInvalidPaginationParameter if a param is not a positive integer
+func PaginationFromRequest(r *http.Request, defaultLimit, maxLimit uint) (models.Pagination, error) {
+	query := r.URL.Query()
+
+	page, err := parsePaginationParam(query.Get("page"), 1)
+	if err != nil {
+		return models.Pagination{}, err
+	}
+
+	limit, err := parsePaginationParam(query.Get("limit"), defaultLimit)
+	if err != nil {
+		return models.Pagination{}, err
+	}
+
+	if maxLimit > 0 && limit > maxLimit {
+		limit = maxLimit
+	}
+
+	return models.Pagination{Page: page, Limit: limit, MaxLimit: maxLimit}, nil
+}
+
+func parsePaginationParam(value string, defaultValue uint) (uint, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || n == 0 {
+		return 0, models.ErrInvalidPaginationParameter
+	}
+
+	return uint(n), nil
+}
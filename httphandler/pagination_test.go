@@ -0,0 +1,58 @@
+package httphandler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginationFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    models.Pagination
+		wantErr error
+	}{
+		{
+			name: "valid params",
+			url:  "/users?page=3&limit=15",
+			want: models.Pagination{Page: 3, Limit: 15, MaxLimit: 50},
+		},
+		{
+			name: "missing params",
+			url:  "/users",
+			want: models.Pagination{Page: 1, Limit: 10, MaxLimit: 50},
+		},
+		{
+			name: "limit greater than max limit",
+			url:  "/users?limit=500",
+			want: models.Pagination{Page: 1, Limit: 50, MaxLimit: 50},
+		},
+		{
+			name:    "page is not a number",
+			url:     "/users?page=two",
+			wantErr: models.ErrInvalidPaginationParameter,
+		},
+		{
+			name:    "negative limit",
+			url:     "/users?limit=-5",
+			wantErr: models.ErrInvalidPaginationParameter,
+		},
+		{
+			name:    "page zero",
+			url:     "/users?page=0",
+			wantErr: models.ErrInvalidPaginationParameter,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.url, nil)
+			got, err := PaginationFromRequest(r, 10, 50)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+// AggregateColumn contains the information of a column that aggregates the values of a group,
+// ej: array_agg(tag ORDER BY tag ASC) AS tags
+type AggregateColumn struct {
+	Function AggregateFunction `json:"function"`
+	Name     string            `json:"name"`
+
+	// Source sets the origin of the column, see Field.Source
+	Source string `json:"source"` // Optional
+
+	// OrderBy sorts the values into the aggregate
+	OrderBy SortFields `json:"order_by"` // Optional
+
+	// Separator is used ONLY for `StringAgg`
+	Separator string `json:"separator"`
+	Alias     string `json:"alias"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// Column contains the information of a column of a select, ej: c.employer_id AS employer
+type Column struct {
+	Name string `json:"name"`
+
+	// Source sets the origin of the column, see Field.Source
+	Source string `json:"source"` // Optional
+
+	// RawExpression selects the expression instead of the Name, ej: count(*).
+	// It is written as is, without lowercase nor Source, so the caller is responsible
+	// for its safety and it must never come from the input of the user
+	RawExpression string `json:"-"` // Optional
+
+	Alias string `json:"alias"` // Optional
+}
@@ -0,0 +1,34 @@
+package models
+
+// Condition is a node of a tree of conditions, the tree sets the precedence
+// with its structure instead of the group flags of the fields, ej:
+// NewOr(NewAnd(NewLeaf(a), NewLeaf(b)), NewAnd(NewLeaf(c), NewOr(NewLeaf(d), NewLeaf(e))))
+// is (a AND b) OR (c AND (d OR e))
+type Condition interface {
+	isCondition()
+}
+
+// AndCondition joins its conditions with AND
+type AndCondition []Condition
+
+// OrCondition joins its conditions with OR
+type OrCondition []Condition
+
+// LeafCondition is the condition of a field, the ChainingKey and the group flags
+// of the field are ignored
+type LeafCondition struct {
+	Field Field
+}
+
+func (AndCondition) isCondition()  {}
+func (OrCondition) isCondition()   {}
+func (LeafCondition) isCondition() {}
+
+// NewAnd returns the conditions joined with AND, without conditions it is always true
+func NewAnd(conditions ...Condition) Condition { return AndCondition(conditions) }
+
+// NewOr returns the conditions joined with OR, without conditions it is always false
+func NewOr(conditions ...Condition) Condition { return OrCondition(conditions) }
+
+// NewLeaf returns the condition of the field
+func NewLeaf(field Field) Condition { return LeafCondition{Field: field} }
@@ -5,10 +5,27 @@ import (
 )
 
 var (
-	ErrInvalidPaginationParameter  = errors.New("invalid pagination parameters")
-	ErrFromValueIsEmpty            = errors.New("`from` value is empty")
-	ErrToValueIsEmpty              = errors.New("`to` value is empty")
-	ErrFromAndToValuesAreMissMatch = errors.New("`from` and `to` values are missmatch")
+	ErrInvalidPaginationParameter   = errors.New("invalid pagination parameters")
+	ErrFromValueIsEmpty             = errors.New("`from` value is empty")
+	ErrToValueIsEmpty               = errors.New("`to` value is empty")
+	ErrFromAndToValuesAreMissMatch  = errors.New("`from` and `to` values are missmatch")
+	ErrFromIsGreaterThanTo          = errors.New("`from` value is greater than `to` value")
+	ErrFromAndToValuesAreNotColumns = errors.New("`from` and `to` values must be column names")
+	ErrInvalidWindowFrame           = errors.New("invalid window frame")
+	ErrPlaceholdersAndArgsMissMatch = errors.New("placeholders and args are missmatch")
+	ErrDistinctOnOrderMissMatch     = errors.New("the ORDER BY must begin with the DISTINCT ON columns")
+	ErrInvalidRangeType             = errors.New("invalid range type")
+	ErrInvalidRangeBound            = errors.New("the range bounds must be time.Time or string")
+	ErrInvalidArrayIndex            = errors.New("the array index must be greater than zero")
+	ErrInvalidINParameter           = errors.New("invalid IN parameter")
+	ErrInvalidTimeZone              = errors.New("invalid time zone")
+	ErrInvalidEnumType              = errors.New("invalid enum type")
+	ErrGroupCloseWithoutOpen        = errors.New("a group is closed without being opened")
+	ErrEmptyFields                  = errors.New("the fields are empty")
+	ErrEmptySubquery                = errors.New("the subquery is empty")
+	ErrMalformedCursor              = errors.New("malformed cursor")
+	ErrInvalidArrayComparison       = errors.New("invalid comparison of ANY or ALL")
+	ErrInvalidPartialIndexFilter    = errors.New("the partial index filter must be IS NULL or IS NOT NULL")
 )
 
 // Errors SQL
@@ -28,11 +45,38 @@ const (
 	GreaterThan          operatorField = ">"
 	LessThanOrEqualTo    operatorField = "<="
 	GreaterThanOrEqualTo operatorField = ">="
+	Like                 operatorField = "LIKE"
+	NotLike              operatorField = "NOT LIKE"
 	Ilike                operatorField = "ILIKE"
+	NotIlike             operatorField = "NOT ILIKE"
+	LikeOperator         operatorField = "~~"
+	IlikeOperator        operatorField = "~~*"
+	NotLikeOperator      operatorField = "!~~"
+	NotIlikeOperator     operatorField = "!~~*"
 	In                   operatorField = "IN"
+	NotIn                operatorField = "NOT IN"
 	IsNull               operatorField = "IS NULL"
 	IsNotNull            operatorField = "IS NOT NULL"
 	Between              operatorField = "BETWEEN"
+	BetweenSymmetric     operatorField = "BETWEEN SYMMETRIC"
+	NotBetween           operatorField = "NOT BETWEEN"
+	IsDistinctFrom       operatorField = "IS DISTINCT FROM"
+	IsNotDistinctFrom    operatorField = "IS NOT DISTINCT FROM"
+	Overlaps             operatorField = "&&"
+	Exists               operatorField = "EXISTS"
+	NotExists            operatorField = "NOT EXISTS"
+	Any                  operatorField = "ANY"
+	All                  operatorField = "ALL"
+	JSONContains         operatorField = "@>"
+	JSONContainedBy      operatorField = "<@"
+	JSONHasKey           operatorField = "?" // written as jsonb_exists(name, ?) with the positional placeholders
+)
+
+// Range types of postgres used by the Overlaps operator
+const (
+	TsRange   = "tsrange"
+	TsTzRange = "tstzrange"
+	DateRange = "daterange"
 )
 
 // ChainingField is the keyword for chaining the next field
@@ -52,3 +96,83 @@ var (
 	Asc  OrderField = "ASC"
 	Desc OrderField = "DESC"
 )
+
+// NullsOrder is the keyword for the position of the nulls in an order
+type NullsOrder string
+
+// NullsOrders
+const (
+	NullsFirst NullsOrder = "NULLS FIRST"
+	NullsLast  NullsOrder = "NULLS LAST"
+)
+
+// JoinType is the keyword for joining a table
+type JoinType string
+
+// JoinTypes
+const (
+	InnerJoin JoinType = "INNER JOIN"
+	LeftJoin  JoinType = "LEFT JOIN"
+	RightJoin JoinType = "RIGHT JOIN"
+	FullJoin  JoinType = "FULL JOIN"
+)
+
+// LockMode is the keyword for locking the selected rows
+type LockMode string
+
+// LockModes
+const (
+	ForUpdate      LockMode = "FOR UPDATE"
+	ForNoKeyUpdate LockMode = "FOR NO KEY UPDATE"
+	ForShare       LockMode = "FOR SHARE"
+	ForKeyShare    LockMode = "FOR KEY SHARE"
+)
+
+// FrameMode is the keyword for the mode of a window frame
+type FrameMode string
+
+// FrameModes
+const (
+	Rows   FrameMode = "ROWS"
+	Range  FrameMode = "RANGE"
+	Groups FrameMode = "GROUPS"
+)
+
+// FrameBound is the keyword for the start or the end of a window frame
+type FrameBound string
+
+// FrameBounds
+const (
+	UnboundedPreceding FrameBound = "UNBOUNDED PRECEDING"
+	CurrentRow         FrameBound = "CURRENT ROW"
+	UnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING"
+)
+
+// GroupingMode is the keyword for an advanced grouping of the GROUP BY
+type GroupingMode string
+
+// GroupingModes
+const (
+	Rollup       GroupingMode = "ROLLUP"
+	Cube         GroupingMode = "CUBE"
+	GroupingSets GroupingMode = "GROUPING SETS"
+)
+
+// RankFunction is a window function that ranks the rows
+type RankFunction string
+
+// RankFunctions
+const (
+	Rank      RankFunction = "RANK()"
+	DenseRank RankFunction = "DENSE_RANK()"
+	RowNumber RankFunction = "ROW_NUMBER()"
+)
+
+// AggregateFunction is a function that aggregates the values of a group in one value
+type AggregateFunction string
+
+// AggregateFunctions
+const (
+	ArrayAgg  AggregateFunction = "array_agg"
+	StringAgg AggregateFunction = "string_agg"
+)
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// cursorValue is a value of a cursor with its type, the value is kept as text
+// so the integers don't lose precision
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// EncodeCursor returns the values of the cursor of a keyset as an opaque text safe for URLs,
+// the types int, int64, uint, uint64, float64, bool, string and time.Time are preserved,
+// the values of other types are encoded as string
+func EncodeCursor(values []interface{}) string {
+	encoded := make([]cursorValue, 0, len(values))
+	for _, value := range values {
+		encoded = append(encoded, encodeCursorValue(value))
+	}
+
+	data, _ := json.Marshal(encoded)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor returns the values of a cursor encoded with EncodeCursor,
+// it returns ErrMalformedCursor if the cursor is malformed
+func DecodeCursor(s string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedCursor, err)
+	}
+
+	var encoded []cursorValue
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedCursor, err)
+	}
+
+	values := make([]interface{}, 0, len(encoded))
+	for _, value := range encoded {
+		decoded, err := decodeCursorValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformedCursor, err)
+		}
+		values = append(values, decoded)
+	}
+
+	return values, nil
+}
+
+func encodeCursorValue(value interface{}) cursorValue {
+	switch v := value.(type) {
+	case int:
+		return cursorValue{Type: "int", Value: strconv.Itoa(v)}
+	case int64:
+		return cursorValue{Type: "int64", Value: strconv.FormatInt(v, 10)}
+	case uint:
+		return cursorValue{Type: "uint", Value: strconv.FormatUint(uint64(v), 10)}
+	case uint64:
+		return cursorValue{Type: "uint64", Value: strconv.FormatUint(v, 10)}
+	case float64:
+		return cursorValue{Type: "float64", Value: strconv.FormatFloat(v, 'g', -1, 64)}
+	case bool:
+		return cursorValue{Type: "bool", Value: strconv.FormatBool(v)}
+	case time.Time:
+		return cursorValue{Type: "time", Value: v.Format(time.RFC3339Nano)}
+	case string:
+		return cursorValue{Type: "string", Value: v}
+	default:
+		return cursorValue{Type: "string", Value: fmt.Sprint(v)}
+	}
+}
+
+func decodeCursorValue(value cursorValue) (interface{}, error) {
+	switch value.Type {
+	case "int":
+		return strconv.Atoi(value.Value)
+	case "int64":
+		return strconv.ParseInt(value.Value, 10, 64)
+	case "uint":
+		n, err := strconv.ParseUint(value.Value, 10, 0)
+		return uint(n), err
+	case "uint64":
+		return strconv.ParseUint(value.Value, 10, 64)
+	case "float64":
+		return strconv.ParseFloat(value.Value, 64)
+	case "bool":
+		return strconv.ParseBool(value.Value)
+	case "time":
+		return time.Parse(time.RFC3339Nano, value.Value)
+	case "string":
+		return value.Value, nil
+	default:
+		return nil, fmt.Errorf("the type %q is not supported", value.Type)
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2021, 4, 28, 10, 30, 0, 123456789, time.UTC)
+	values := []interface{}{createdAt, 42, int64(9007199254740993), uint(7), "O'Brien", 1.5, true}
+
+	got, err := DecodeCursor(EncodeCursor(values))
+
+	assert.NoError(t, err)
+	assert.Equal(t, values, got)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "%%%"},
+		{name: "not json", cursor: "bm90IGpzb24"},
+		{name: "unsupported type", cursor: "W3sidCI6Im1hcCIsInYiOiJ7fSJ9XQ"},
+		{name: "wrong value", cursor: "W3sidCI6ImludCIsInYiOiJhYmMifV0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor)
+			assert.ErrorIs(t, err, ErrMalformedCursor)
+		})
+	}
+}
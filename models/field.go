@@ -3,16 +3,23 @@ package models
 import (
 	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// timeZoneRegexp matches a time zone name, abbreviation or offset, ej: America/Bogota, UTC, -05:00
+var timeZoneRegexp = regexp.MustCompile(`^[A-Za-z0-9_+\-/:]+$`)
+
 // Field contains the information of a field for a query
 type Field struct {
 	Name     string        `json:"name"`
 	Operator operatorField `json:"operator"`
 	Value    interface{}   `json:"value"`
 
-	// FromValue and ToValue are used ONLY for `Between` structure
+	// FromValue and ToValue are used ONLY for the `Between` operators and `Overlaps`,
+	// if IsValueFromTable is true they are the names of the columns that limit the range
 	FromValue interface{} `json:"from_value"`
 	ToValue   interface{} `json:"to_value"`
 
@@ -20,7 +27,8 @@ type Field struct {
 	ChainingKey ChainingField `json:"chaining_key"`
 
 	// Source sets the origin of the field, is used if a resource has more of one source,
-	// this is useful generally when an infrastructure implementation used "Joins"
+	// this is useful generally when an infrastructure implementation used "Joins".
+	// The source can be a table alias or the name of a CTE (WITH clause) or derived table
 	Source string `json:"source"` // Optional
 
 	// GroupOpen allows beginning a conditions group of fields and the infrastructure
@@ -31,6 +39,12 @@ type Field struct {
 	// implementation must include into group the field that sets the GroupClose = true
 	GroupClose bool `json:"group_close"` // Optional
 
+	// ExtraGroupOpen and ExtraGroupClose are the number of groups that the field opens or closes
+	// besides GroupOpen and GroupClose, they allow nesting groups in the same field,
+	// ej: AndGroup wraps a side that already begins with a group: ((a OR b) AND c)
+	ExtraGroupOpen  int `json:"extra_group_open"`  // Optional
+	ExtraGroupClose int `json:"extra_group_close"` // Optional
+
 	// IsValueFromTable allows to compare the value from Name with the value of other table
 	// ej: un.ends_at >= pp.ends_at
 	// to implement, this field must be true
@@ -43,9 +57,250 @@ type Field struct {
 	// SourceNameValueFromTable sets the origin of the NameValueFromTable
 	// is used if a resource has mor of one source and IsValueFromTable is true
 	SourceNameValueFromTable string `json:"source_name_value_from_table"`
+
+	// EnumType casts the value to the enum type of postgres,
+	// ej: status = $1::order_status
+	EnumType string `json:"enum_type"` // Optional
+
+	// IgnoreCase allows a case-insensitive IN, the column is wrapped with lower()
+	// and the values are lowercased, ej: lower(code) IN ('col','cop').
+	// It applies only when the value is a slice of strings
+	IgnoreCase bool `json:"ignore_case"` // Optional
+
+	// NullSafe builds the `NotIn` operator as NOT EXISTS (SELECT 1 WHERE name IN (...)),
+	// unlike NOT IN it matches the rows where the column is NULL, and a NULL value of the list
+	// or of the RawSubquery doesn't make it match nothing
+	NullSafe bool `json:"null_safe"` // Optional
+
+	// Unaccent allows an accent-insensitive comparison wrapping the column and the value
+	// with unaccent(), ej: unaccent(name) ILIKE unaccent($1).
+	// It requires the extension of postgres: CREATE EXTENSION unaccent
+	Unaccent bool `json:"unaccent"` // Optional
+
+	// Escape appends the escape character to a LIKE or ILIKE comparison, so the wildcards
+	// escaped with a backslash in the value are literal, ej: name ILIKE $1 ESCAPE '\'
+	Escape bool `json:"escape"` // Optional
+
+	// ArrayIndex compares the element of the array column at the index instead of the column,
+	// the arrays of postgres begin at 1, ej: tags[1] = $1
+	ArrayIndex *int `json:"array_index"` // Optional
+
+	// AtTimeZone converts the column to the time zone before the comparison,
+	// ej: created_at AT TIME ZONE 'UTC' >= $1
+	AtTimeZone string `json:"at_time_zone"` // Optional
+
+	// Subquery compares the column against the value of a correlated subquery instead of the Value,
+	// ej: amount > (SELECT avg(amount) FROM orders o2 WHERE o2.user_id = o.user_id)
+	Subquery *ScalarSubquery `json:"-"` // Optional
+
+	// RawSubquery is the subquery of the `Exists` and `NotExists` operators, and of the `In`
+	// and `NotIn` operators when the Value is nil, ej: id IN (SELECT user_id FROM orders).
+	// It is written as is, without parameters, so the caller is responsible
+	// for its safety and it must never come from the input of the user
+	RawSubquery string `json:"-"` // Optional
+
+	// Raw is a condition written as is instead of the Name, the Operator and the Value,
+	// ej: age(birth_date) > interval '18 years'. It still honors ChainingKey, GroupOpen and GroupClose,
+	// and the caller is responsible for its safety so it must never come from the input of the user.
+	// Each `?` of the Raw is a marker replaced by the placeholder of its arg of Args in order,
+	// ej: coalesce(discount, ?) > ? is written as coalesce(discount, $1) > $2. The `?` into quotes
+	// aren't markers, and `??` is written as a literal `?`, ej: data ?? 'plan' AND seats > ?
+	Raw  string        `json:"-"` // Optional
+	Args []interface{} `json:"-"` // Optional
+
+	// JSONKey compares the text of the key of the jsonb column instead of the column,
+	// ej: data->>'status' = $1
+	JSONKey string `json:"json_key"` // Optional
+
+	// ArrayComparison is the comparison of the `Any` and `All` operators against the elements
+	// of the array Value, ej: score > ALL($1). By default it is `Equals`
+	ArrayComparison operatorField `json:"array_comparison"` // Optional
+
+	// RangeType is the range type built with FromValue and ToValue by the `Overlaps` operator,
+	// ej: during && tsrange($1, $2)
+	RangeType string `json:"range_type"` // Optional
+
+	// RangeEndColumn builds the range of the `Overlaps` operator from the columns Name and RangeEndColumn
+	// instead of a range column, ej: tstzrange(check_in, check_out) && tstzrange($1, $2)
+	RangeEndColumn string `json:"range_end_column"` // Optional
+}
+
+// RangeOverlap returns a field that filters the rows whose range column overlaps
+// the range built with from and to, ej: during && tsrange($1, $2)
+func RangeOverlap(column string, from, to interface{}, rangeType string) Field {
+	return Field{
+		Name:      column,
+		Operator:  Overlaps,
+		FromValue: from,
+		ToValue:   to,
+		RangeType: rangeType,
+	}
+}
+
+// ColumnsRangeOverlap returns a field that filters the rows whose range of the columns start and end
+// overlaps the range built with from and to, ej: tstzrange(check_in, check_out) && tstzrange($1, $2)
+func ColumnsRangeOverlap(startColumn, endColumn string, from, to interface{}, rangeType string) Field {
+	field := RangeOverlap(startColumn, from, to, rangeType)
+	field.RangeEndColumn = endColumn
+
+	return field
+}
+
+// GroupOpens returns the number of groups that the field opens
+func (f Field) GroupOpens() int {
+	if f.GroupOpen {
+		return f.ExtraGroupOpen + 1
+	}
+
+	return f.ExtraGroupOpen
+}
+
+// GroupCloses returns the number of groups that the field closes
+func (f Field) GroupCloses() int {
+	if f.GroupClose {
+		return f.ExtraGroupClose + 1
+	}
+
+	return f.ExtraGroupClose
+}
+
+// IsBetween returns if the operator of the field compares against a range with
+// the `from` and `to` values: BETWEEN, BETWEEN SYMMETRIC or NOT BETWEEN
+func (f Field) IsBetween() bool {
+	switch f.Operator {
+	case Between, BetweenSymmetric, NotBetween:
+		return true
+	}
+
+	return false
+}
+
+// ValidateArrayComparison returns if the comparison of the `Any` and `All` operators
+// is a comparison operator, an empty comparison is valid because it is `Equals`
+func (f Field) ValidateArrayComparison() error {
+	switch f.ArrayComparison {
+	case "", Equals, NotEqualTo, LessThan, GreaterThan, LessThanOrEqualTo, GreaterThanOrEqualTo:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidArrayComparison, f.ArrayComparison)
+}
+
+// IsPattern returns if the operator of the field compares against a LIKE pattern
+// with the keyword syntax, the operators ~~ and ~~* don't allow the ESCAPE clause
+func (f Field) IsPattern() bool {
+	switch f.Operator {
+	case Like, NotLike, Ilike, NotIlike:
+		return true
+	}
+
+	return false
+}
+
+// ValidateArrayIndex returns if the array index is valid, a nil index is valid
+func (f Field) ValidateArrayIndex() error {
+	if f.ArrayIndex != nil && *f.ArrayIndex < 1 {
+		return ErrInvalidArrayIndex
+	}
+
+	return nil
+}
+
+// ValidateAtTimeZone returns if the time zone is valid, an empty time zone is valid
+func (f Field) ValidateAtTimeZone() error {
+	if f.AtTimeZone != "" && !timeZoneRegexp.MatchString(f.AtTimeZone) {
+		return ErrInvalidTimeZone
+	}
+
+	return nil
+}
+
+// ValidateRangeType returns if the range type is supported by the `Overlaps` operator
+func (f Field) ValidateRangeType() error {
+	switch f.RangeType {
+	case TsRange, TsTzRange, DateRange:
+		return nil
+	}
+
+	return ErrInvalidRangeType
+}
+
+// ValidateRangeBounds returns if `from` and `to` values are valid bounds of the range types,
+// all of them are ranges of time so the bounds must be time.Time or string
+func (f Field) ValidateRangeBounds() error {
+	for _, bound := range []interface{}{f.FromValue, f.ToValue} {
+		switch bound.(type) {
+		case time.Time, string:
+		default:
+			return fmt.Errorf("%w: %T", ErrInvalidRangeBound, bound)
+		}
+	}
+
+	return nil
+}
+
+// HalfOpenRange returns the fields that filter the column in the range [start, end),
+// ej: (created_at >= $1 AND created_at < $2), unlike BETWEEN the end is excluded
+// so the timestamps of the end are not taken twice by consecutive ranges
+func HalfOpenRange(column string, start, end interface{}) Fields {
+	return Fields{
+		{Name: column, Operator: GreaterThanOrEqualTo, Value: start, ChainingKey: And, GroupOpen: true},
+		{Name: column, Operator: LessThan, Value: end, GroupClose: true},
+	}
 }
 
-// ValidateFromAndToValues returns if `from` and `to` values are valid
+// ParseIntIN returns an IN field of the name with the integers of the comma-separated csv,
+// ej: the query param ?ids=1,2,3 returns {Name: "ids", Operator: In, Value: []int{1, 2, 3}}.
+// It returns ErrInvalidINParameter if the csv is empty or a value is not an integer
+func ParseIntIN(name, csv string) (Field, error) {
+	tokens, err := splitINParameter(csv)
+	if err != nil {
+		return Field{}, err
+	}
+
+	values := make([]int, 0, len(tokens))
+	for _, token := range tokens {
+		value, err := strconv.Atoi(token)
+		if err != nil {
+			return Field{}, fmt.Errorf("%w: %q is not an integer", ErrInvalidINParameter, token)
+		}
+		values = append(values, value)
+	}
+
+	return Field{Name: name, Operator: In, Value: values}, nil
+}
+
+// ParseStringIN returns an IN field of the name with the texts of the comma-separated csv,
+// ej: the query param ?codes=COL,COP returns {Name: "codes", Operator: In, Value: []string{"COL", "COP"}}.
+// It returns ErrInvalidINParameter if the csv is empty or has an empty value
+func ParseStringIN(name, csv string) (Field, error) {
+	tokens, err := splitINParameter(csv)
+	if err != nil {
+		return Field{}, err
+	}
+
+	return Field{Name: name, Operator: In, Value: tokens}, nil
+}
+
+// splitINParameter returns the values of the comma-separated csv without spaces
+func splitINParameter(csv string) ([]string, error) {
+	if strings.TrimSpace(csv) == "" {
+		return nil, fmt.Errorf("%w: the value is empty", ErrInvalidINParameter)
+	}
+
+	tokens := strings.Split(csv, ",")
+	for k, token := range tokens {
+		tokens[k] = strings.TrimSpace(token)
+		if tokens[k] == "" {
+			return nil, fmt.Errorf("%w: the value %q has an empty item", ErrInvalidINParameter, csv)
+		}
+	}
+
+	return tokens, nil
+}
+
+// ValidateFromAndToValues returns if `from` and `to` values are valid, they must have the same type
+// and `from` can't be greater than `to` when the type is ordered: int, int64, float64, time.Time or string
 func (f Field) ValidateFromAndToValues() error {
 	if f.FromValue == nil {
 		return ErrFromValueIsEmpty
@@ -58,6 +313,45 @@ func (f Field) ValidateFromAndToValues() error {
 		return ErrFromAndToValuesAreMissMatch
 	}
 
+	// the names of the columns and the bounds of BETWEEN SYMMETRIC aren't ordered
+	if f.IsValueFromTable || f.Operator == BetweenSymmetric {
+		return nil
+	}
+
+	if isGreater(f.FromValue, f.ToValue) {
+		return ErrFromIsGreaterThanTo
+	}
+
+	return nil
+}
+
+// isGreater returns if a is greater than b, only the values of the same
+// ordered type are compared: int, int64, float64, time.Time and string
+func isGreater(a, b interface{}) bool {
+	switch a := a.(type) {
+	case int:
+		return a > b.(int)
+	case int64:
+		return a > b.(int64)
+	case float64:
+		return a > b.(float64)
+	case time.Time:
+		return a.After(b.(time.Time))
+	case string:
+		return a > b.(string)
+	}
+
+	return false
+}
+
+// ValidateFromAndToColumns returns if `from` and `to` values are valid column names
+func (f Field) ValidateFromAndToColumns() error {
+	from, okFrom := f.FromValue.(string)
+	to, okTo := f.ToValue.(string)
+	if !okFrom || !okTo || from == "" || to == "" {
+		return ErrFromAndToValuesAreNotColumns
+	}
+
 	return nil
 }
 
@@ -72,6 +366,70 @@ func (fs *Fields) Push(f ...Field) {
 	*fs = append(*fs, f...)
 }
 
+// WithDefaultSource returns a copy of the fields setting the source
+// to the fields that don't have one
+func (fs Fields) WithDefaultSource(source string) Fields {
+	if fs == nil {
+		return nil
+	}
+
+	fields := make(Fields, 0, len(fs))
+	for _, field := range fs {
+		if field.Source == "" {
+			field.Source = source
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// AndGroup returns the combination of the fields and the other fields,
+// each side is wrapped in a group and both are joined with AND: (fields) AND (other),
+// so the OR chains of a side can't escape its scope. Each side must close its own groups.
+// A side that already begins or ends with a group is nested, ej: ((a OR b) AND (c OR d)) AND (e)
+func (fs Fields) AndGroup(other Fields) Fields {
+	if fs.IsEmpty() {
+		return append(Fields{}, other...)
+	}
+	if other.IsEmpty() {
+		return append(Fields{}, fs...)
+	}
+
+	fields := make(Fields, 0, len(fs)+len(other))
+	fields = append(fields, fs...)
+	fields = append(fields, other...)
+
+	last := len(fs) - 1
+	fields[last].ChainingKey = And
+	openGroup(&fields[0])
+	closeGroup(&fields[last])
+	openGroup(&fields[last+1])
+	closeGroup(&fields[len(fields)-1])
+
+	return fields
+}
+
+// openGroup opens a group in the field, nesting it if the field already opens a group
+func openGroup(field *Field) {
+	if field.GroupOpen {
+		field.ExtraGroupOpen++
+		return
+	}
+
+	field.GroupOpen = true
+}
+
+// closeGroup closes a group in the field, nesting it if the field already closes a group
+func closeGroup(field *Field) {
+	if field.GroupClose {
+		field.ExtraGroupClose++
+		return
+	}
+
+	field.GroupClose = true
+}
+
 // ValidateNames validates if the fields is allowed for query
 func (fs Fields) ValidateNames(allowedFields []string) error {
 	for _, field := range fs {
@@ -108,6 +466,28 @@ func (fs Fields) ValidateSources(sourcesAllowed []string) error {
 	return nil
 }
 
+// ValidateInNotEmpty validates if the IN and NOT IN fields have a non-empty slice as value or a subquery
+func (fs Fields) ValidateInNotEmpty() error {
+	for _, field := range fs {
+		if field.Operator != In && field.Operator != NotIn {
+			continue
+		}
+		if field.Value == nil && field.RawSubquery != "" {
+			continue
+		}
+
+		value := reflect.ValueOf(field.Value)
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return fmt.Errorf("the field %s must have a slice as value for %s", field.Name, field.Operator)
+		}
+		if value.Len() == 0 {
+			return fmt.Errorf("the field %s has an empty value for %s", field.Name, field.Operator)
+		}
+	}
+
+	return nil
+}
+
 // FindField returns the Field, and it returns if field was found
 func (fs Fields) FindField(inputField string) (Field, bool) {
 	for _, field := range fs {
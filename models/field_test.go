@@ -0,0 +1,245 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFields_ValidateInNotEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  Fields
+		wantErr string
+	}{
+		{
+			name: "valid IN values",
+			fields: Fields{
+				{Name: "id", Value: []uint{1, 2}, Operator: In},
+				{Name: "code", Value: []string{"COL"}, Operator: In},
+				{Name: "name", Value: ""},
+			},
+		},
+		{
+			name: "empty slice",
+			fields: Fields{
+				{Name: "id", Value: []uint{1, 2}, Operator: In},
+				{Name: "code", Value: []string{}, Operator: In},
+			},
+			wantErr: "the field code has an empty value for IN",
+		},
+		{
+			name: "empty slice for NOT IN",
+			fields: Fields{
+				{Name: "id", Value: []int{}, Operator: NotIn},
+			},
+			wantErr: "the field id has an empty value for NOT IN",
+		},
+		{
+			name: "wrong type",
+			fields: Fields{
+				{Name: "id", Value: 1, Operator: In},
+			},
+			wantErr: "the field id must have a slice as value for IN",
+		},
+		{
+			name: "nil value",
+			fields: Fields{
+				{Name: "id", Operator: In},
+			},
+			wantErr: "the field id must have a slice as value for IN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fields.ValidateInNotEmpty()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFields_ValidateInNotEmpty_RawSubquery(t *testing.T) {
+	fields := Fields{{Name: "id", Operator: In, RawSubquery: "SELECT user_id FROM orders"}}
+	assert.NoError(t, fields.ValidateInNotEmpty())
+}
+
+func TestFields_WithDefaultSource(t *testing.T) {
+	fields := Fields{
+		{Name: "employer_id", Value: 1},
+		{Source: "cs", Name: "description", Value: "ACTIVE"},
+		{Name: "is_active", Value: true},
+	}
+
+	got := fields.WithDefaultSource("c")
+
+	assert.Equal(t, Fields{
+		{Source: "c", Name: "employer_id", Value: 1},
+		{Source: "cs", Name: "description", Value: "ACTIVE"},
+		{Source: "c", Name: "is_active", Value: true},
+	}, got)
+	assert.Equal(t, "", fields[0].Source, "the original fields must not change")
+	assert.Nil(t, Fields(nil).WithDefaultSource("c"))
+}
+
+func TestFields_AndGroup(t *testing.T) {
+	system := Fields{{Name: "tenant_id", Value: 1}}
+	user := Fields{
+		{Name: "name", Value: "Alejandro", ChainingKey: Or},
+		{Name: "is_admin", Value: true},
+	}
+
+	got := system.AndGroup(user)
+
+	assert.Equal(t, Fields{
+		{Name: "tenant_id", Value: 1, GroupOpen: true, GroupClose: true, ChainingKey: And},
+		{Name: "name", Value: "Alejandro", ChainingKey: Or, GroupOpen: true},
+		{Name: "is_admin", Value: true, GroupClose: true},
+	}, got)
+	assert.False(t, system[0].GroupOpen, "the original fields must not change")
+
+	assert.Equal(t, user, Fields{}.AndGroup(user))
+	assert.Equal(t, system, system.AndGroup(nil))
+}
+
+func TestFields_AndGroup_NestedGroups(t *testing.T) {
+	system := Fields{{Name: "tenant_id", Value: 1}}
+	user := Fields{
+		{Name: "a", Value: 1, ChainingKey: Or, GroupOpen: true},
+		{Name: "b", Value: 2, ChainingKey: And, GroupClose: true},
+		{Name: "c", Value: 3, ChainingKey: Or, GroupOpen: true},
+		{Name: "d", Value: 4, GroupClose: true},
+	}
+
+	got := user.AndGroup(system)
+
+	assert.Equal(t, Fields{
+		{Name: "a", Value: 1, ChainingKey: Or, GroupOpen: true, ExtraGroupOpen: 1},
+		{Name: "b", Value: 2, ChainingKey: And, GroupClose: true},
+		{Name: "c", Value: 3, ChainingKey: Or, GroupOpen: true},
+		{Name: "d", Value: 4, ChainingKey: And, GroupClose: true, ExtraGroupClose: 1},
+		{Name: "tenant_id", Value: 1, GroupOpen: true, GroupClose: true},
+	}, got)
+	assert.Equal(t, 2, got[0].GroupOpens())
+	assert.Equal(t, 2, got[3].GroupCloses())
+	assert.Equal(t, 0, user[0].ExtraGroupOpen, "the original fields must not change")
+}
+
+func TestField_ValidateFromAndToValues(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		field   Field
+		wantErr error
+	}{
+		{name: "valid int range", field: Field{Operator: Between, FromValue: 1, ToValue: 5}},
+		{name: "valid time range", field: Field{Operator: Between, FromValue: from, ToValue: to}},
+		{name: "equal bounds", field: Field{Operator: Between, FromValue: 5, ToValue: 5}},
+		{name: "reversed int range", field: Field{Operator: Between, FromValue: 5, ToValue: 1}, wantErr: ErrFromIsGreaterThanTo},
+		{name: "reversed time range", field: Field{Operator: NotBetween, FromValue: to, ToValue: from}, wantErr: ErrFromIsGreaterThanTo},
+		{name: "reversed symmetric range", field: Field{Operator: BetweenSymmetric, FromValue: 5, ToValue: 1}},
+		{name: "reversed columns", field: Field{Operator: Between, FromValue: "ends_at", ToValue: "begins_at", IsValueFromTable: true}},
+		{name: "not ordered type", field: Field{Operator: Between, FromValue: true, ToValue: false}},
+		{name: "type mismatch", field: Field{Operator: Between, FromValue: 1, ToValue: "5"}, wantErr: ErrFromAndToValuesAreMissMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.field.ValidateFromAndToValues(), tt.wantErr)
+		})
+	}
+}
+
+func TestHalfOpenRange(t *testing.T) {
+	got := HalfOpenRange("created_at", "2021-01-01", "2021-02-01")
+
+	assert.Equal(t, Fields{
+		{Name: "created_at", Operator: GreaterThanOrEqualTo, Value: "2021-01-01", ChainingKey: And, GroupOpen: true},
+		{Name: "created_at", Operator: LessThan, Value: "2021-02-01", GroupClose: true},
+	}, got)
+}
+
+func TestParseIntIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    Field
+		wantErr bool
+	}{
+		{
+			name: "valid csv",
+			csv:  "1, 2,3",
+			want: Field{Name: "id", Operator: In, Value: []int{1, 2, 3}},
+		},
+		{
+			name:    "empty csv",
+			csv:     "",
+			wantErr: true,
+		},
+		{
+			name:    "non-numeric value",
+			csv:     "1,two,3",
+			wantErr: true,
+		},
+		{
+			name:    "empty item",
+			csv:     "1,,3",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIntIN("id", tt.csv)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidINParameter)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseStringIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    Field
+		wantErr bool
+	}{
+		{
+			name: "valid csv",
+			csv:  "COL, COP",
+			want: Field{Name: "code", Operator: In, Value: []string{"COL", "COP"}},
+		},
+		{
+			name:    "empty csv",
+			csv:     " ",
+			wantErr: true,
+		},
+		{
+			name:    "trailing comma",
+			csv:     "COL,",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStringIN("code", tt.csv)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidINParameter)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
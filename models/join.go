@@ -0,0 +1,30 @@
+package models
+
+// Join contains the information of a join between two sources
+type Join struct {
+	Type JoinType `json:"type"`
+
+	// Table is the name of the joined table, when Lateral is true
+	// it is the subquery to join, ej: SELECT * FROM payments p WHERE p.user_id = u.id
+	Table string `json:"table"`
+	Alias string `json:"alias"` // Optional
+
+	// OnLeft and OnRight are the columns compared in the ON condition,
+	// ej: c.employer_id = e.id
+	OnLeft  string `json:"on_left"`
+	OnRight string `json:"on_right"`
+
+	// Operator compares OnLeft and OnRight, by default is Equals.
+	// Use IsNotDistinctFrom to match the rows where both columns are NULL
+	Operator operatorField `json:"operator"` // Optional
+
+	// Lateral allows the joined subquery to reference the columns of the previous sources,
+	// if OnLeft and OnRight are empty the join uses ON true
+	Lateral bool `json:"lateral"` // Optional
+}
+
+// Joins slice of Join
+type Joins []Join
+
+// IsEmpty returns if the Joins is empty
+func (js Joins) IsEmpty() bool { return len(js) == 0 }
@@ -0,0 +1,31 @@
+package models
+
+// Keyset contains the information of a keyset (seek) pagination,
+// the next page begins after the values of the cursor instead of using an offset
+type Keyset struct {
+	// Column is the column to sort, it can be non-unique, ej: created_at
+	Column string `json:"column"`
+
+	// TieBreaker is a unique column that sorts the rows with the same Column value,
+	// ej: id. With it the cursor carries the values of both columns
+	TieBreaker string `json:"tie_breaker"` // Optional
+
+	// Source sets the origin of the columns, see Field.Source
+	Source string `json:"source"` // Optional
+
+	Order OrderField `json:"order"`
+
+	// After is the cursor: the values of Column and TieBreaker of the last row
+	// of the previous page. It is empty for the first page
+	After []interface{} `json:"after"`
+	Limit uint          `json:"limit"`
+}
+
+// Columns returns the columns of the keyset
+func (k Keyset) Columns() []string {
+	if k.TieBreaker == "" {
+		return []string{k.Column}
+	}
+
+	return []string{k.Column, k.TieBreaker}
+}
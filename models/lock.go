@@ -0,0 +1,13 @@
+package models
+
+// Lock contains the information of the lock of the rows of a select
+type Lock struct {
+	Mode LockMode `json:"mode"`
+
+	// OfTables limits the lock to the rows of these tables in a joined select,
+	// ej: FOR UPDATE OF contracts
+	OfTables []string `json:"of_tables"` // Optional
+}
+
+// IsEmpty returns if the Lock has no mode
+func (l Lock) IsEmpty() bool { return l.Mode == "" }
@@ -1,8 +1,67 @@
 package models
 
+import (
+	"fmt"
+	"math"
+)
+
 // Pagination contains the information of the pagination
 type Pagination struct {
 	Page     uint `json:"page"`
 	Limit    uint `json:"limit"`
 	MaxLimit uint
+
+	// Offset is the number of rows to skip, if it is set it is used instead of the Page
+	Offset uint `json:"offset"` // Optional
+}
+
+// IsZero returns if the pagination doesn't set a page, a limit nor an offset
+func (p Pagination) IsZero() bool {
+	return p.Page == 0 && p.Limit == 0 && p.Offset == 0
+}
+
+// Validate returns ErrInvalidPaginationParameter if the limit is greater than the MaxLimit,
+// or if the offset of the page and the limit overflows the bigint offset of postgres.
+// An empty limit is validated with the MaxLimit, the builders validate it with the limit they use
+func (p Pagination) Validate() error {
+	if p.Limit > 0 && p.MaxLimit > 0 && p.Limit > p.MaxLimit {
+		return fmt.Errorf("%w: the limit %d is greater than the max limit %d", ErrInvalidPaginationParameter, p.Limit, p.MaxLimit)
+	}
+
+	limit := p.Limit
+	if limit == 0 {
+		limit = p.MaxLimit
+	}
+
+	return p.ValidateOffset(limit)
+}
+
+// ValidateOffset returns ErrInvalidPaginationParameter if the offset, or the offset of the page
+// with the limit used to paginate, overflows the bigint offset of postgres
+func (p Pagination) ValidateOffset(limit uint) error {
+	if uint64(p.Offset) > math.MaxInt64 {
+		return fmt.Errorf("%w: the offset %d overflows", ErrInvalidPaginationParameter, p.Offset)
+	}
+
+	if p.Page > 1 && limit > 0 && uint64(p.Page-1) > math.MaxInt64/uint64(limit) {
+		return fmt.Errorf("%w: the page %d with the limit %d overflows the offset", ErrInvalidPaginationParameter, p.Page, limit)
+	}
+
+	return nil
+}
+
+// ValidateLimitIn validates if the limit is one of the allowed page sizes,
+// an empty limit is valid because the default limit is used
+func (p Pagination) ValidateLimitIn(allowed []uint) error {
+	if p.Limit == 0 {
+		return nil
+	}
+
+	for _, limit := range allowed {
+		if p.Limit == limit {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: the limit %d is not allowed", ErrInvalidPaginationParameter, p.Limit)
 }
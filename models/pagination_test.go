@@ -0,0 +1,58 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pag     Pagination
+		wantErr error
+	}{
+		{name: "valid", pag: Pagination{Page: 3, Limit: 10, MaxLimit: 50}},
+		{name: "zero", pag: Pagination{}},
+		{name: "limit without max limit", pag: Pagination{Page: 1, Limit: 500}},
+		{name: "over limit", pag: Pagination{Page: 1, Limit: 100, MaxLimit: 50}, wantErr: ErrInvalidPaginationParameter},
+		{name: "overflowing page", pag: Pagination{Page: math.MaxUint32, Limit: math.MaxUint32}, wantErr: ErrInvalidPaginationParameter},
+		{name: "overflowing page with the max limit", pag: Pagination{Page: 1 << 62, MaxLimit: 20}, wantErr: ErrInvalidPaginationParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pag.Validate()
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPagination_IsZero(t *testing.T) {
+	assert.True(t, Pagination{}.IsZero())
+	assert.True(t, Pagination{MaxLimit: 50}.IsZero())
+	assert.False(t, Pagination{Page: 1}.IsZero())
+	assert.False(t, Pagination{Limit: 10}.IsZero())
+	assert.False(t, Pagination{Offset: 5}.IsZero())
+}
+
+func TestPagination_ValidateLimitIn(t *testing.T) {
+	allowed := []uint{10, 25, 50, 100}
+
+	tests := []struct {
+		name    string
+		limit   uint
+		wantErr error
+	}{
+		{name: "allowed limit", limit: 25},
+		{name: "empty limit", limit: 0},
+		{name: "disallowed limit", limit: 30, wantErr: ErrInvalidPaginationParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Pagination{Page: 1, Limit: tt.limit}.ValidateLimitIn(allowed)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
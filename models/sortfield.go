@@ -13,6 +13,15 @@ type SortField struct {
 	// Source sets the origin of the field, is used if a resource has more of one source,
 	// this is useful generally when an infrastructure implementation used "Joins"
 	Source string `json:"source"` // Optional
+
+	// RawExpression sorts by the expression instead of the Name, ej: lower(name).
+	// It is written as is, without lowercase nor Source, so the caller is responsible
+	// for its safety and it must never come from the input of the user
+	RawExpression string `json:"-"` // Optional
+
+	// Nulls sets if the nulls are sorted first or last, by default postgres sorts
+	// the nulls last in ASC and first in DESC
+	Nulls NullsOrder `json:"nulls"` // Optional
 }
 
 // SortFields slice of SortField
@@ -38,3 +47,22 @@ func (ss SortFields) ValidateNames(allowedFields []string) error {
 
 	return nil
 }
+
+// ValidateSources validates if the fields source is allowed for ordering,
+// the allowed sources can be table aliases or CTE names
+func (ss SortFields) ValidateSources(sourcesAllowed []string) error {
+	for _, field := range ss {
+		isAllowed := false
+		for _, sourceAllowed := range sourcesAllowed {
+			if strings.EqualFold(sourceAllowed, field.Source) {
+				isAllowed = true
+				break
+			}
+		}
+		if !isAllowed {
+			return fmt.Errorf("the source %s is not allowed for ordering", field.Source)
+		}
+	}
+
+	return nil
+}
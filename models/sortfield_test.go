@@ -0,0 +1,35 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortFields_ValidateSources(t *testing.T) {
+	tests := []struct {
+		name           string
+		sorts          SortFields
+		sourcesAllowed []string
+		wantErr        bool
+	}{
+		{
+			name:           "table and CTE sources allowed",
+			sorts:          SortFields{{Name: "id", Source: "c"}, {Name: "total", Source: "active_contracts"}},
+			sourcesAllowed: []string{"c", "active_contracts"},
+			wantErr:        false,
+		},
+		{
+			name:           "CTE source not allowed",
+			sorts:          SortFields{{Name: "total", Source: "active_contracts"}},
+			sourcesAllowed: []string{"c"},
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sorts.ValidateSources(tt.sourcesAllowed)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+// ScalarSubquery is a subquery that returns one value of the Table correlated with
+// the row of the outer query, ej: (SELECT avg(amount) FROM orders o2 WHERE o2.user_id = o.user_id)
+type ScalarSubquery struct {
+	// Expression is the value returned by the subquery, ej: avg(amount).
+	// It is written as is, so it must never come from the input of the user
+	Expression string `json:"-"`
+
+	Table string `json:"table"`
+
+	// Alias is the correlation alias of the Table, it must be different
+	// from the alias of the outer query, ej: o2
+	Alias string `json:"alias"`
+
+	// Column of the Table that is compared with the OuterColumn of the outer query,
+	// ej: user_id = o.user_id
+	Column      string `json:"column"`
+	OuterColumn string `json:"outer_column"`
+}
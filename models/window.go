@@ -0,0 +1,67 @@
+package models
+
+// WindowColumn contains the information of a column computed with a window function,
+// ej: SUM(amount) OVER (PARTITION BY user_id ORDER BY created_at) AS total
+type WindowColumn struct {
+	// Function is the expression to compute, ej: SUM(amount)
+	Function    string      `json:"function"`
+	PartitionBy []string    `json:"partition_by"` // Optional
+	OrderBy     SortFields  `json:"order_by"`     // Optional
+	Frame       WindowFrame `json:"frame"`        // Optional
+	Alias       string      `json:"alias"`
+}
+
+// NewRankColumn returns a WindowColumn that ranks the rows of each partition by the order,
+// ej: RANK() OVER (PARTITION BY category ORDER BY score DESC) AS rank
+func NewRankColumn(function RankFunction, partitionBy []string, orderBy SortFields, alias string) WindowColumn {
+	return WindowColumn{
+		Function:    string(function),
+		PartitionBy: partitionBy,
+		OrderBy:     orderBy,
+		Alias:       alias,
+	}
+}
+
+// WindowFrame contains the frame of a window function,
+// ej: ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW
+type WindowFrame struct {
+	Mode  FrameMode  `json:"mode"`
+	Start FrameBound `json:"start"`
+
+	// End is optional, if it is empty the frame ends in the current row
+	End FrameBound `json:"end"`
+}
+
+// IsEmpty returns if the WindowFrame is empty
+func (wf WindowFrame) IsEmpty() bool { return wf.Mode == "" && wf.Start == "" && wf.End == "" }
+
+// Validate returns if the mode and the bounds of the frame are allowed,
+// the frame can't start at UNBOUNDED FOLLOWING nor end at UNBOUNDED PRECEDING
+func (wf WindowFrame) Validate() error {
+	switch wf.Mode {
+	case Rows, Range, Groups:
+	default:
+		return ErrInvalidWindowFrame
+	}
+
+	if !isFrameBound(wf.Start) {
+		return ErrInvalidWindowFrame
+	}
+	if wf.End != "" && !isFrameBound(wf.End) {
+		return ErrInvalidWindowFrame
+	}
+	if wf.Start == UnboundedFollowing || wf.End == UnboundedPreceding {
+		return ErrInvalidWindowFrame
+	}
+
+	return nil
+}
+
+func isFrameBound(bound FrameBound) bool {
+	switch bound {
+	case UnboundedPreceding, CurrentRow, UnboundedFollowing:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowFrame_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		frame   WindowFrame
+		wantErr error
+	}{
+		{name: "running total", frame: WindowFrame{Mode: Rows, Start: UnboundedPreceding, End: CurrentRow}},
+		{name: "without end", frame: WindowFrame{Mode: Range, Start: CurrentRow}},
+		{name: "whole partition", frame: WindowFrame{Mode: Rows, Start: UnboundedPreceding, End: UnboundedFollowing}},
+		{name: "start at unbounded following", frame: WindowFrame{Mode: Rows, Start: UnboundedFollowing}, wantErr: ErrInvalidWindowFrame},
+		{name: "end at unbounded preceding", frame: WindowFrame{Mode: Rows, Start: CurrentRow, End: UnboundedPreceding}, wantErr: ErrInvalidWindowFrame},
+		{name: "invalid mode", frame: WindowFrame{Mode: "LINES", Start: CurrentRow}, wantErr: ErrInvalidWindowFrame},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.frame.Validate(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+)
+
+// BuildSQLAggregateQuery builds and returns a query SELECT of postgres with its WHERE, GROUP BY, HAVING,
+// ORDER BY and pagination, and the arguments of the WHERE followed by the arguments of the HAVING,
+// so the placeholders are continuous, ej:
+// SELECT status, count(*) AS total FROM contracts WHERE employer_id = $1 GROUP BY status HAVING count(*) > $2.
+// The clauses that are empty are omitted. If a clause fails it returns the error as query and nil arguments
+func BuildSQLAggregateQuery(table string, cols []models.Column, where models.Fields, groupBy []string, having models.Fields, order models.SortFields, pag models.Pagination) (string, []interface{}) {
+	columns, err := buildSQLColumns(cols)
+	if err != "" {
+		return err, nil
+	}
+
+	p := &params{}
+	conditions, errWhere := buildTableWhere(table, where, p)
+	if errWhere != nil {
+		return errWhere.Error(), nil
+	}
+
+	group := BuildSQLGroupBy(groupBy)
+	if group == ErrInvalidIdentifier {
+		return ErrInvalidIdentifier, nil
+	}
+
+	havingConditions, errHaving := buildConditions("HAVING", having, p)
+	if errHaving != nil {
+		return errHaving.Error(), nil
+	}
+
+	pagination := BuildSQLPagination(pag)
+	if pagination == ErrInvalidPagination {
+		return ErrInvalidPagination, nil
+	}
+
+	clauses := []string{fmt.Sprintf("SELECT %s FROM %s", columns, table)}
+	for _, clause := range []string{conditions, group, havingConditions, BuildSQLOrderBy(order), pagination} {
+		if clause != "" {
+			clauses = append(clauses, clause)
+		}
+	}
+
+	return strings.Join(clauses, " "), p.args
+}
+
+// buildSQLColumns returns the columns of a select separated by commas,
+// if a column fails it returns the error
+func buildSQLColumns(cols []models.Column) (string, string) {
+	if len(cols) == 0 {
+		return "", ErrFieldsAreEmpty
+	}
+
+	columns := make([]string, 0, len(cols))
+	for _, col := range cols {
+		column := col.RawExpression
+		if column == "" {
+			if !isValidIdentifier(col.Name) {
+				return "", ErrInvalidIdentifier
+			}
+
+			column = strings.ToLower(col.Name)
+			if col.Source != "" {
+				column = fmt.Sprintf("%s.%s", col.Source, column)
+			}
+		}
+
+		if col.Alias != "" {
+			column = fmt.Sprintf("%s AS %s", column, col.Alias)
+		}
+
+		columns = append(columns, column)
+	}
+
+	return strings.Join(columns, ", "), ""
+}
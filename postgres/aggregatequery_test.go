@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSQLAggregateQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		table     string
+		cols      []models.Column
+		where     models.Fields
+		groupBy   []string
+		having    models.Fields
+		order     models.SortFields
+		pag       models.Pagination
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:  "every clause",
+			table: "contracts c",
+			cols: []models.Column{
+				{Name: "employer_id", Source: "c"},
+				{Name: "Status"},
+				{RawExpression: "count(*)", Alias: "total"},
+			},
+			where: models.Fields{
+				{Name: "created_at", Operator: models.GreaterThanOrEqualTo, Value: "2021-01-01", ChainingKey: models.And},
+				{Name: "employer_id", Source: "c", Operator: models.In, Value: []uint{1, 2}},
+			},
+			groupBy: []string{"c.employer_id", "status"},
+			having: models.Fields{
+				{Name: "count(*)", Operator: models.GreaterThan, Value: 5, ChainingKey: models.And},
+				{Name: "sum(amount)", Operator: models.LessThan, Value: 1000},
+			},
+			order:     models.SortFields{{Name: "total", Order: models.Desc}},
+			pag:       models.Pagination{Page: 2, Limit: 10},
+			wantQuery: "SELECT c.employer_id, status, count(*) AS total FROM contracts c WHERE created_at >= $1 AND c.employer_id IN (1,2) GROUP BY c.employer_id, status HAVING count(*) > $2 AND sum(amount) < $3 ORDER BY total DESC LIMIT 10 OFFSET 10",
+			wantArgs:  []interface{}{"2021-01-01", 5, 1000},
+		},
+		{
+			name:      "only the columns and the group",
+			table:     "contracts",
+			cols:      []models.Column{{Name: "status"}, {RawExpression: "count(*)", Alias: "total"}},
+			groupBy:   []string{"status"},
+			wantQuery: "SELECT status, count(*) AS total FROM contracts GROUP BY status",
+			wantArgs:  nil,
+		},
+		{
+			name:      "without columns",
+			table:     "contracts",
+			wantQuery: ErrFieldsAreEmpty,
+			wantArgs:  nil,
+		},
+		{
+			name:      "invalid column",
+			table:     "contracts",
+			cols:      []models.Column{{Name: "status; DROP TABLE contracts"}},
+			wantQuery: ErrInvalidIdentifier,
+			wantArgs:  nil,
+		},
+		{
+			name:      "invalid group",
+			table:     "contracts",
+			cols:      []models.Column{{Name: "status"}},
+			groupBy:   []string{"status--"},
+			wantQuery: ErrInvalidIdentifier,
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := BuildSQLAggregateQuery(tt.table, tt.cols, tt.where, tt.groupBy, tt.having, tt.order, tt.pag)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+)
+
+// QueryBuilder assembles a query SELECT of postgres with its filters, sort and pagination,
+// ej: new(QueryBuilder).Select("users", fields).Where(filters).OrderBy(sorts).Paginate(pag).Build()
+type QueryBuilder struct {
+	table      string
+	fields     []string
+	filters    models.Fields
+	sorts      models.SortFields
+	pagination models.Pagination
+}
+
+// Select sets the table and the fields of the query
+func (qb *QueryBuilder) Select(table string, fields []string) *QueryBuilder {
+	qb.table = table
+	qb.fields = fields
+	return qb
+}
+
+// Where sets the filters of the query
+func (qb *QueryBuilder) Where(filters models.Fields) *QueryBuilder {
+	qb.filters = filters
+	return qb
+}
+
+// OrderBy sets the sort of the query
+func (qb *QueryBuilder) OrderBy(sorts models.SortFields) *QueryBuilder {
+	qb.sorts = sorts
+	return qb
+}
+
+// Paginate sets the pagination of the query
+func (qb *QueryBuilder) Paginate(pag models.Pagination) *QueryBuilder {
+	qb.pagination = pag
+	return qb
+}
+
+// Build builds and returns the query and its arguments, the clauses that
+// are empty are omitted and the partial index filters of the table are added.
+// If a clause fails it returns the error as query and nil arguments
+func (qb *QueryBuilder) Build() (string, []interface{}) {
+	selectFields := BuildSQLSelectFields(qb.table, qb.fields)
+	if selectFields == ErrFieldsAreEmpty {
+		return ErrFieldsAreEmpty, nil
+	}
+
+	p := &params{}
+	conditions, err := buildTableWhere(qb.table, qb.filters, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	pagination := BuildSQLPagination(qb.pagination)
+	if pagination == ErrInvalidPagination {
+		return ErrInvalidPagination, nil
+	}
+
+	clauses := []string{selectFields}
+	for _, clause := range []string{conditions, BuildSQLOrderBy(qb.sorts), pagination} {
+		if clause != "" {
+			clauses = append(clauses, clause)
+		}
+	}
+
+	return strings.Join(clauses, " "), p.args
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBuilder_Build(t *testing.T) {
+	tests := []struct {
+		name      string
+		builder   *QueryBuilder
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name: "complete select",
+			builder: new(QueryBuilder).
+				Select("contracts", []string{"id", "employer_id", "status"}).
+				Where(models.Fields{
+					{Name: "employer_id", Value: 1},
+					{Name: "status", Value: "active"},
+				}).
+				OrderBy(models.SortFields{{Name: "created_at", Order: models.Desc}}).
+				Paginate(models.Pagination{Page: 2, Limit: 10}),
+			wantQuery: "SELECT id, employer_id, status FROM contracts WHERE employer_id = $1 AND status = $2 ORDER BY created_at DESC LIMIT 10 OFFSET 10",
+			wantArgs:  []interface{}{1, "active"},
+		},
+		{
+			name:      "only select",
+			builder:   new(QueryBuilder).Select("contracts", []string{"id"}),
+			wantQuery: "SELECT id FROM contracts",
+			wantArgs:  nil,
+		},
+		{
+			name: "select and sort without filters",
+			builder: new(QueryBuilder).
+				Select("contracts", []string{"id"}).
+				OrderBy(models.SortFields{{Name: "id"}}),
+			wantQuery: "SELECT id FROM contracts ORDER BY id ASC",
+			wantArgs:  nil,
+		},
+		{
+			name:      "empty fields",
+			builder:   new(QueryBuilder).Select("contracts", nil),
+			wantQuery: ErrFieldsAreEmpty,
+			wantArgs:  nil,
+		},
+		{
+			name: "invalid filter",
+			builder: new(QueryBuilder).
+				Select("contracts", []string{"id"}).
+				Where(models.Fields{{Name: "begins_at", Operator: models.Between, ToValue: 1}}),
+			wantQuery: models.ErrFromValueIsEmpty.Error(),
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := tt.builder.Build()
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
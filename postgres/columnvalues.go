@@ -0,0 +1,38 @@
+package postgres
+
+import "fmt"
+
+// ColumnValues accumulates the columns and their values of a statement in order
+type ColumnValues struct {
+	columns []string
+	values  []interface{}
+}
+
+// Add adds a column and its value
+func (cv *ColumnValues) Add(column string, value interface{}) {
+	cv.columns = append(cv.columns, column)
+	cv.values = append(cv.values, value)
+}
+
+// Columns returns the accumulated columns
+func (cv ColumnValues) Columns() []string { return cv.columns }
+
+// Values returns the accumulated values in the same order of the columns
+func (cv ColumnValues) Values() []interface{} { return cv.values }
+
+// BuildUpdateByID builds a query UPDATE of postgres with the accumulated columns,
+// and it returns the values followed by the id as arguments
+func (cv ColumnValues) BuildUpdateByID(table string, id interface{}) (string, []interface{}) {
+	if len(cv.columns) == 0 {
+		return ErrFieldsAreEmpty, nil
+	}
+
+	set, nextParam := BuildSQLSet(cv.columns, 1)
+
+	args := make([]interface{}, 0, len(cv.values)+1)
+	args = append(args, cv.values...)
+	args = append(args, id)
+
+	return fmt.Sprintf("UPDATE %s SET %s, %s = now() WHERE %s = %s",
+		table, set, config.UpdatedAtColumn, config.IDColumn, placeholder(nextParam)), args
+}
@@ -0,0 +1,24 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnValues_BuildUpdateByID(t *testing.T) {
+	cv := ColumnValues{}
+	cv.Add("name", "Alejandro")
+	cv.Add("country", "COLOMBIA")
+	cv.Add("age", 30)
+
+	gotQuery, gotArgs := cv.BuildUpdateByID("users", 77)
+	assert.Equal(t, "UPDATE users SET name = $1, country = $2, age = $3, updated_at = now() WHERE id = $4", gotQuery)
+	assert.Equal(t, []interface{}{"Alejandro", "COLOMBIA", 30, 77}, gotArgs)
+	assert.Equal(t, []string{"name", "country", "age"}, cv.Columns())
+	assert.Equal(t, []interface{}{"Alejandro", "COLOMBIA", 30}, cv.Values())
+
+	gotQuery, gotArgs = ColumnValues{}.BuildUpdateByID("users", 77)
+	assert.Equal(t, ErrFieldsAreEmpty, gotQuery)
+	assert.Nil(t, gotArgs)
+}
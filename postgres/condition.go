@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+)
+
+// BuildSQLConditionTree builds and returns a query WHERE of postgres from the tree of conditions
+// and its arguments, each branch with several conditions is wrapped with parentheses, ej:
+// WHERE (a = $1 AND b = $2) OR (c = $3 AND (d = $4 OR e = $5)).
+// If the root is nil it returns an empty string and nil arguments because the WHERE is optional
+func BuildSQLConditionTree(root models.Condition) (string, []interface{}) {
+	if root == nil {
+		return "", nil
+	}
+
+	p := &params{}
+	query, err := buildConditionTree(root, p, false)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	query = "WHERE " + query
+	if isNumberedDialect() {
+		if err := checkPlaceholders(query, p.args, 1); err != nil {
+			return err.Error(), nil
+		}
+	}
+
+	return query, p.args
+}
+
+// buildConditionTree builds the condition binding its arguments in p,
+// nested is true when the condition is a branch of other condition
+func buildConditionTree(condition models.Condition, p *params, nested bool) (string, error) {
+	var children []models.Condition
+	var chaining models.ChainingField
+	empty := ""
+
+	switch node := condition.(type) {
+	case models.LeafCondition:
+		field := node.Field
+		setDefaultValuesField(&field)
+		return buildCondition(field, p)
+	case models.AndCondition:
+		children, chaining, empty = node, models.And, "TRUE"
+	case models.OrCondition:
+		children, chaining, empty = node, models.Or, "FALSE"
+	default:
+		return "", fmt.Errorf("psql: the condition %T is not supported", condition)
+	}
+
+	if len(children) == 0 {
+		return empty, nil
+	}
+	if len(children) == 1 {
+		return buildConditionTree(children[0], p, nested)
+	}
+
+	conditions := make([]string, 0, len(children))
+	for _, child := range children {
+		built, err := buildConditionTree(child, p, true)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, built)
+	}
+
+	query := strings.Join(conditions, fmt.Sprintf(" %s ", chaining))
+	if nested {
+		query = fmt.Sprintf("(%s)", query)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSQLConditionTree(t *testing.T) {
+	tests := []struct {
+		name      string
+		root      models.Condition
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "without conditions",
+			root:      nil,
+			wantQuery: "",
+			wantArgs:  nil,
+		},
+		{
+			name:      "one leaf",
+			root:      models.NewLeaf(models.Field{Name: "a", Value: 1}),
+			wantQuery: "WHERE a = $1",
+			wantArgs:  []interface{}{1},
+		},
+		{
+			name: "(a AND b) OR (c AND (d OR e))",
+			root: models.NewOr(
+				models.NewAnd(
+					models.NewLeaf(models.Field{Name: "a", Value: 1}),
+					models.NewLeaf(models.Field{Name: "b", Operator: models.GreaterThan, Value: 2}),
+				),
+				models.NewAnd(
+					models.NewLeaf(models.Field{Source: "t", Name: "c", Operator: models.IsNull}),
+					models.NewOr(
+						models.NewLeaf(models.Field{Name: "d", Operator: models.Between, FromValue: 3, ToValue: 4}),
+						models.NewLeaf(models.Field{Name: "e", Value: "five", ChainingKey: models.And, GroupOpen: true}),
+					),
+				),
+			),
+			wantQuery: "WHERE (a = $1 AND b > $2) OR (t.c IS NULL AND (d BETWEEN $3 AND $4 OR e = $5))",
+			wantArgs:  []interface{}{1, 2, 3, 4, "five"},
+		},
+		{
+			name: "branch with one condition",
+			root: models.NewAnd(
+				models.NewOr(models.NewLeaf(models.Field{Name: "a", Value: 1})),
+				models.NewLeaf(models.Field{Name: "b", Value: 2}),
+			),
+			wantQuery: "WHERE a = $1 AND b = $2",
+			wantArgs:  []interface{}{1, 2},
+		},
+		{
+			name: "empty branches",
+			root: models.NewOr(
+				models.NewAnd(),
+				models.NewOr(),
+			),
+			wantQuery: "WHERE TRUE OR FALSE",
+			wantArgs:  nil,
+		},
+		{
+			name: "invalid leaf",
+			root: models.NewAnd(
+				models.NewLeaf(models.Field{Name: "a", Value: 1}),
+				models.NewLeaf(models.Field{Name: "b", Operator: models.Between, ToValue: 2}),
+			),
+			wantQuery: models.ErrFromValueIsEmpty.Error(),
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLConditionTree(tt.root)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
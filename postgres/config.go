@@ -0,0 +1,41 @@
+package postgres
+
+// Config contains the names of the columns that the builders write by default
+type Config struct {
+	IDColumn        string
+	CreatedAtColumn string
+	UpdatedAtColumn string
+}
+
+// config is the Config used by the builders
+var config = defaultConfig()
+
+// SetConfig sets the names of the columns that the builders write by default,
+// an empty name uses the default name, so SetConfig(Config{}) restores the defaults
+func SetConfig(c Config) {
+	defaults := defaultConfig()
+	if c.IDColumn == "" {
+		c.IDColumn = defaults.IDColumn
+	}
+	if c.CreatedAtColumn == "" {
+		c.CreatedAtColumn = defaults.CreatedAtColumn
+	}
+	if c.UpdatedAtColumn == "" {
+		c.UpdatedAtColumn = defaults.UpdatedAtColumn
+	}
+
+	config = c
+}
+
+func defaultConfig() Config {
+	return Config{
+		IDColumn:        "id",
+		CreatedAtColumn: "created_at",
+		UpdatedAtColumn: "updated_at",
+	}
+}
+
+// defaultReturning returns the columns of the RETURNING of an insert: id, created_at
+func defaultReturning() []string {
+	return []string{config.IDColumn, config.CreatedAtColumn}
+}
@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConfig(t *testing.T) {
+	SetConfig(Config{IDColumn: "pk", CreatedAtColumn: "date_created", UpdatedAtColumn: "date_modified"})
+	defer SetConfig(Config{})
+
+	fields := []string{"name", "email"}
+
+	assert.Equal(t, "SELECT pk, name, email, date_created, date_modified FROM users", BuildSQLSelect("users", fields))
+	assert.Equal(t, "INSERT INTO users (name, email) VALUES ($1, $2) RETURNING pk, date_created", BuildSQLInsert("users", fields))
+	assert.Equal(t, "INSERT INTO users (pk, name, email) VALUES ($1, $2, $3) RETURNING date_created", BuildSQLInsertWithID("users", fields))
+	assert.Equal(t, "UPDATE users SET name = $1, email = $2, date_modified = now() WHERE pk = $3", BuildSQLUpdateByID("users", fields))
+	assert.Equal(t, "u.pk, u.name, u.email, u.date_created, u.date_modified", ColumnsAliased(fields, "u"))
+	assert.Equal(t, "DELETE FROM users WHERE pk = $1", BuildSQLDelete("users"))
+}
+
+func TestSetConfig_Defaults(t *testing.T) {
+	SetConfig(Config{IDColumn: "pk"})
+	defer SetConfig(Config{})
+
+	assert.Equal(t, "SELECT pk, name, created_at, updated_at FROM users", BuildSQLSelect("users", []string{"name"}))
+
+	SetConfig(Config{})
+	assert.Equal(t, "SELECT id, name, created_at, updated_at FROM users", BuildSQLSelect("users", []string{"name"}))
+}
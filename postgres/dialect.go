@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dialect builds the placeholders of the arguments of a database
+type Dialect interface {
+	// Placeholder returns the placeholder of the argument n, n begins at 1
+	Placeholder(n int) string
+}
+
+// PostgresDialect builds the numbered placeholders of postgres: $1, $2
+type PostgresDialect struct{}
+
+// Placeholder returns $n
+func (PostgresDialect) Placeholder(n int) string { return "$" + strconv.Itoa(n) }
+
+// MySQLDialect builds the positional placeholders of MySQL: ?, ?
+type MySQLDialect struct{}
+
+// Placeholder returns ?
+func (MySQLDialect) Placeholder(int) string { return "?" }
+
+// DefaultDialect is the Dialect used by the builders for the placeholders of the WHERE,
+// INSERT and UPDATE. With positional placeholders (MySQL) the identical values are never
+// shared by BuildSQLWhereReusingArgs because each placeholder needs its own argument.
+// The RETURNING of the insert builders is of postgres
+var DefaultDialect Dialect = PostgresDialect{}
+
+// placeholder returns the placeholder of the argument n of the DefaultDialect
+func placeholder(n int) string { return DefaultDialect.Placeholder(n) }
+
+// isNumberedDialect returns if the placeholders of the DefaultDialect are numbered,
+// so a placeholder can be used several times
+func isNumberedDialect() bool { return placeholder(1) != placeholder(2) }
+
+// placeholderPattern returns the regexp that matches the numbered placeholders of the DefaultDialect,
+// the placeholder is the prefix of the placeholder 1 followed by the number, ej: $1, :1
+func placeholderPattern() *regexp.Regexp {
+	prefix := strings.TrimSuffix(placeholder(1), "1")
+	if prefix == "$" {
+		return placeholderRegexp
+	}
+
+	return regexp.MustCompile(regexp.QuoteMeta(prefix) + `([0-9]+)`)
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultDialect(t *testing.T) {
+	fields := models.Fields{
+		{Name: "name", Operator: models.Ilike, Value: "%ana%", ChainingKey: models.Or},
+		{Name: "description", Operator: models.Ilike, Value: "%ana%"},
+		{Name: "begins_at", Operator: models.Between, FromValue: 1, ToValue: 2},
+	}
+
+	tests := []struct {
+		name            string
+		dialect         Dialect
+		wantWhere       string
+		wantWhereReused string
+		wantArgsReused  []interface{}
+		wantInsert      string
+		wantUpdate      string
+		wantDelete      string
+		wantSoftDelete  string
+	}{
+		{
+			name:            "postgres",
+			dialect:         PostgresDialect{},
+			wantWhere:       "WHERE name ILIKE $1 OR description ILIKE $2 AND begins_at BETWEEN $3 AND $4",
+			wantWhereReused: "WHERE name ILIKE $1 OR description ILIKE $1 AND begins_at BETWEEN $2 AND $3",
+			wantArgsReused:  []interface{}{"%ana%", 1, 2},
+			wantInsert:      "INSERT INTO users (name, email) VALUES ($1, $2) RETURNING id, created_at",
+			wantUpdate:      "UPDATE users SET name = $1, email = $2, updated_at = now() WHERE id = $3",
+			wantDelete:      "DELETE FROM users WHERE id = $1",
+			wantSoftDelete:  "UPDATE users SET deleted_at = now() WHERE id = $1",
+		},
+		{
+			name:            "mysql",
+			dialect:         MySQLDialect{},
+			wantWhere:       "WHERE name ILIKE ? OR description ILIKE ? AND begins_at BETWEEN ? AND ?",
+			wantWhereReused: "WHERE name ILIKE ? OR description ILIKE ? AND begins_at BETWEEN ? AND ?",
+			wantArgsReused:  []interface{}{"%ana%", "%ana%", 1, 2},
+			wantInsert:      "INSERT INTO users (name, email) VALUES (?, ?) RETURNING id, created_at",
+			wantUpdate:      "UPDATE users SET name = ?, email = ?, updated_at = now() WHERE id = ?",
+			wantDelete:      "DELETE FROM users WHERE id = ?",
+			wantSoftDelete:  "UPDATE users SET deleted_at = now() WHERE id = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			DefaultDialect = tt.dialect
+			defer func() { DefaultDialect = PostgresDialect{} }()
+
+			gotWhere, gotArgs := BuildSQLWhere(fields)
+			assert.Equal(t, tt.wantWhere, gotWhere)
+			assert.Equal(t, []interface{}{"%ana%", "%ana%", 1, 2}, gotArgs)
+
+			gotWhere, gotArgs = BuildSQLWhereReusingArgs(fields)
+			assert.Equal(t, tt.wantWhereReused, gotWhere)
+			assert.Equal(t, tt.wantArgsReused, gotArgs)
+
+			assert.Equal(t, tt.wantInsert, BuildSQLInsert("users", []string{"name", "email"}))
+			assert.Equal(t, tt.wantUpdate, BuildSQLUpdateByID("users", []string{"name", "email"}))
+			assert.Equal(t, tt.wantDelete, BuildSQLDelete("users"))
+			assert.Equal(t, tt.wantSoftDelete, BuildSQLSoftDeleteByID("users"))
+			assert.Equal(t, "LIMIT 10 OFFSET 10", BuildSQLPagination(models.Pagination{Page: 2, Limit: 10}))
+		})
+	}
+}
+
+// oracleDialect builds the numbered placeholders of oracle: :1, :2
+type oracleDialect struct{}
+
+func (oracleDialect) Placeholder(n int) string { return ":" + strconv.Itoa(n) }
+
+func TestDefaultDialect_CheckPlaceholders(t *testing.T) {
+	DefaultDialect = oracleDialect{}
+	defer func() { DefaultDialect = PostgresDialect{} }()
+
+	query, args := BuildSQLWhere(models.Fields{{Name: "amount", Value: 100, Operator: models.GreaterThan}})
+	assert.Equal(t, "WHERE amount > :1", query)
+	assert.Equal(t, []interface{}{100}, args)
+
+	query, args = BuildSQLWhere(models.Fields{{Name: "amount + :2", Value: 100, Operator: models.GreaterThan}})
+	assert.Equal(t, "placeholders and args are missmatch: 2 placeholders and 1 args", query)
+	assert.Nil(t, args)
+}
+
+func TestDefaultDialect_JSONHasKey(t *testing.T) {
+	fields := models.Fields{{Name: "metadata", Operator: models.JSONHasKey, Value: "plan"}}
+
+	query, args := BuildSQLWhere(fields)
+	assert.Equal(t, "WHERE metadata ? $1", query)
+	assert.Equal(t, []interface{}{"plan"}, args)
+
+	DefaultDialect = MySQLDialect{}
+	defer func() { DefaultDialect = PostgresDialect{} }()
+
+	query, args = BuildSQLWhere(fields)
+	assert.Equal(t, "WHERE jsonb_exists(metadata, ?)", query)
+	assert.Equal(t, []interface{}{"plan"}, args)
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QueryHash returns a deterministic hash of a query and its arguments,
+// it is useful as an idempotency key for deduplicating writes.
+//
+// Each argument is encoded recursively with its type to avoid collisions
+// like 1 and "1", and the items of slices, maps and structs are prefixed with
+// their length, so []string{"a b"} and []string{"a", "b"} are different.
+// The hash is deterministic because:
+//   - the entries of the maps are sorted by their encoding
+//   - time.Time is normalized to UTC and without the monotonic clock
+//   - pointers are dereferenced, so the memory address is not used
+func QueryHash(query string, args []interface{}) string {
+	h := sha256.New()
+	writeHashPart(h, query)
+	for _, arg := range args {
+		writeHashPart(h, hashArg(reflect.ValueOf(arg)))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeHashPart writes the part prefixed with its length,
+// so the boundaries between parts can't be confused
+func writeHashPart(h hash.Hash, part string) {
+	fmt.Fprint(h, lengthPrefixed(part))
+}
+
+func lengthPrefixed(part string) string {
+	return fmt.Sprintf("%d:%s", len(part), part)
+}
+
+// timeType is the type of time.Time, it is encoded in UTC
+var timeType = reflect.TypeOf(time.Time{})
+
+// hashArg encodes the value with its type, the items of the
+// composite values are encoded recursively and prefixed with their length
+func hashArg(value reflect.Value) string {
+	if !value.IsValid() {
+		return "<nil>"
+	}
+
+	if value.Type() == timeType && value.CanInterface() {
+		return fmt.Sprintf("time.Time:%s", value.Interface().(time.Time).UTC().Format(time.RFC3339Nano))
+	}
+
+	items := []string{}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return fmt.Sprintf("%s:<nil>", value.Type())
+		}
+
+		return fmt.Sprintf("%s:%s", value.Type(), hashArg(value.Elem()))
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return fmt.Sprintf("%s:<nil>", value.Type())
+		}
+
+		for i := 0; i < value.Len(); i++ {
+			items = append(items, lengthPrefixed(hashArg(value.Index(i))))
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return fmt.Sprintf("%s:<nil>", value.Type())
+		}
+
+		iter := value.MapRange()
+		for iter.Next() {
+			items = append(items, lengthPrefixed(hashArg(iter.Key()))+lengthPrefixed(hashArg(iter.Value())))
+		}
+		sort.Strings(items)
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			items = append(items, lengthPrefixed(hashArg(value.Field(i))))
+		}
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		// only the type, the address is not deterministic
+		return value.Type().String()
+	default:
+		return fmt.Sprintf("%s:%#v", value.Type(), value)
+	}
+
+	return fmt.Sprintf("%s[%d]{%s}", value.Type(), len(items), strings.Join(items, ""))
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryHash(t *testing.T) {
+	query := "INSERT INTO payments (user_id, amount, paid_at) VALUES ($1, $2, $3)"
+	paidAt := time.Date(2021, 4, 28, 10, 0, 0, 0, time.UTC)
+	amount := 30.5
+
+	tests := []struct {
+		name      string
+		argsA     []interface{}
+		argsB     []interface{}
+		wantEqual bool
+	}{
+		{
+			name:      "identical args",
+			argsA:     []interface{}{1, 30.5, paidAt},
+			argsB:     []interface{}{1, 30.5, paidAt},
+			wantEqual: true,
+		},
+		{
+			name:      "same time in other location",
+			argsA:     []interface{}{1, 30.5, paidAt},
+			argsB:     []interface{}{1, 30.5, paidAt.In(time.FixedZone("COT", -5*60*60))},
+			wantEqual: true,
+		},
+		{
+			name:      "pointers are dereferenced",
+			argsA:     []interface{}{1, &amount},
+			argsB:     []interface{}{1, &[]float64{30.5}[0]},
+			wantEqual: true,
+		},
+		{
+			name:      "maps with same content",
+			argsA:     []interface{}{map[string]int{"a": 1, "b": 2, "c": 3}},
+			argsB:     []interface{}{map[string]int{"c": 3, "b": 2, "a": 1}},
+			wantEqual: true,
+		},
+		{
+			name:      "different args",
+			argsA:     []interface{}{1, 30.5, paidAt},
+			argsB:     []interface{}{2, 30.5, paidAt},
+			wantEqual: false,
+		},
+		{
+			name:      "same value with different type",
+			argsA:     []interface{}{1},
+			argsB:     []interface{}{"1"},
+			wantEqual: false,
+		},
+		{
+			name:      "args boundaries",
+			argsA:     []interface{}{"ab", "c"},
+			argsB:     []interface{}{"a", "bc"},
+			wantEqual: false,
+		},
+		{
+			name:      "slice elements boundaries",
+			argsA:     []interface{}{[]string{"a b"}},
+			argsB:     []interface{}{[]string{"a", "b"}},
+			wantEqual: false,
+		},
+		{
+			name:      "pointers nested in a slice are dereferenced",
+			argsA:     []interface{}{[]*float64{&amount}},
+			argsB:     []interface{}{[]*float64{&[]float64{30.5}[0]}},
+			wantEqual: true,
+		},
+		{
+			name:      "pointers nested in a struct are dereferenced",
+			argsA:     []interface{}{struct{ Amount *float64 }{&amount}},
+			argsB:     []interface{}{struct{ Amount *float64 }{&[]float64{30.5}[0]}},
+			wantEqual: true,
+		},
+		{
+			name:      "maps with different content",
+			argsA:     []interface{}{map[string]string{"a": "b c"}},
+			argsB:     []interface{}{map[string]string{"a b": "c"}},
+			wantEqual: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotA := QueryHash(query, tt.argsA)
+			gotB := QueryHash(query, tt.argsB)
+			assert.Equal(t, tt.wantEqual, gotA == gotB)
+		})
+	}
+}
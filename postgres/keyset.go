@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+)
+
+const (
+	// ErrInvalidCursor is returned when the cursor doesn't have a value for each column of the keyset
+	ErrInvalidCursor = "FAILED! THE CURSOR MUST HAVE A VALUE FOR EACH KEYSET COLUMN"
+
+	// ErrMalformedCursor is returned when the opaque cursor can't be decoded
+	ErrMalformedCursor = "FAILED! THE CURSOR IS MALFORMED"
+)
+
+// BuildSQLKeyset builds and returns a query WHERE + ORDER BY + LIMIT of postgres for a keyset
+// pagination and its arguments. The filters are grouped and followed by the cursor condition,
+// with a tie breaker the columns are compared as a tuple, ej:
+// WHERE (is_active = $1) AND (created_at, id) > ($2, $3) ORDER BY created_at ASC, id ASC LIMIT 10
+func BuildSQLKeyset(filters models.Fields, keyset models.Keyset) (string, []interface{}) {
+	columns := keyset.Columns()
+	if len(keyset.After) > 0 && len(keyset.After) != len(columns) {
+		return ErrInvalidCursor, nil
+	}
+
+	if keyset.Order == "" {
+		keyset.Order = models.Asc
+	}
+
+	p := &params{}
+	query, err := buildSQLWhere(filters, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	if len(keyset.After) > 0 {
+		condition := buildSQLKeysetCondition(columns, keyset, p)
+		if query == "" {
+			query = "WHERE " + condition
+		} else {
+			query = fmt.Sprintf("WHERE (%s) AND %s", strings.TrimPrefix(query, "WHERE "), condition)
+		}
+	}
+
+	sorts := make(models.SortFields, 0, len(columns))
+	for _, column := range columns {
+		sorts = append(sorts, models.SortField{Name: column, Source: keyset.Source, Order: keyset.Order})
+	}
+	query = strings.TrimSpace(query + " " + BuildSQLOrderBy(sorts))
+
+	if keyset.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", keyset.Limit)
+	}
+
+	return query, p.args
+}
+
+// BuildSQLKeysetCursor builds the same query of BuildSQLKeyset using the opaque cursor
+// encoded with models.EncodeCursor as the After of the keyset, an empty cursor is the first page
+func BuildSQLKeysetCursor(filters models.Fields, keyset models.Keyset, cursor string) (string, []interface{}) {
+	keyset.After = nil
+	if cursor != "" {
+		after, err := models.DecodeCursor(cursor)
+		if err != nil {
+			return ErrMalformedCursor, nil
+		}
+		keyset.After = after
+	}
+
+	return BuildSQLKeyset(filters, keyset)
+}
+
+// buildSQLKeysetCondition builds the condition to seek the rows after the cursor
+func buildSQLKeysetCondition(columns []string, keyset models.Keyset, p *params) string {
+	operator := models.GreaterThan
+	if keyset.Order == models.Desc {
+		operator = models.LessThan
+	}
+
+	names := make([]string, 0, len(columns))
+	placeholders := make([]string, 0, len(columns))
+	for k, column := range columns {
+		name := strings.ToLower(column)
+		if keyset.Source != "" {
+			name = fmt.Sprintf("%s.%s", keyset.Source, name)
+		}
+
+		names = append(names, name)
+		placeholders = append(placeholders, p.bind(keyset.After[k]))
+	}
+
+	if len(columns) == 1 {
+		return fmt.Sprintf("%s %s %s", names[0], operator, placeholders[0])
+	}
+
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(names, ", "), operator, strings.Join(placeholders, ", "))
+}
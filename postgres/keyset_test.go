@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSQLKeyset(t *testing.T) {
+	lastCreatedAt := time.Date(2021, 4, 28, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		filters   models.Fields
+		keyset    models.Keyset
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "first page",
+			filters:   models.Fields{{Name: "is_active", Value: true}},
+			keyset:    models.Keyset{Column: "created_at", TieBreaker: "id", Limit: 10},
+			wantQuery: "WHERE is_active = $1 ORDER BY created_at ASC, id ASC LIMIT 10",
+			wantArgs:  []interface{}{true},
+		},
+		{
+			name: "composite cursor with filters",
+			filters: models.Fields{
+				{Name: "is_active", Value: true, ChainingKey: models.Or},
+				{Name: "is_staff", Value: true},
+			},
+			keyset:    models.Keyset{Column: "created_at", TieBreaker: "id", After: []interface{}{lastCreatedAt, 77}, Limit: 10},
+			wantQuery: "WHERE (is_active = $1 OR is_staff = $2) AND (created_at, id) > ($3, $4) ORDER BY created_at ASC, id ASC LIMIT 10",
+			wantArgs:  []interface{}{true, true, lastCreatedAt, 77},
+		},
+		{
+			name:      "composite cursor descending with source and without filters",
+			keyset:    models.Keyset{Column: "score", TieBreaker: "id", Source: "p", Order: models.Desc, After: []interface{}{90, 12}, Limit: 5},
+			wantQuery: "WHERE (p.score, p.id) < ($1, $2) ORDER BY p.score DESC, p.id DESC LIMIT 5",
+			wantArgs:  []interface{}{90, 12},
+		},
+		{
+			name:      "cursor without tie breaker",
+			keyset:    models.Keyset{Column: "id", After: []interface{}{77}},
+			wantQuery: "WHERE id > $1 ORDER BY id ASC",
+			wantArgs:  []interface{}{77},
+		},
+		{
+			name:      "cursor without a value for each column",
+			keyset:    models.Keyset{Column: "created_at", TieBreaker: "id", After: []interface{}{lastCreatedAt}},
+			wantQuery: ErrInvalidCursor,
+			wantArgs:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLKeyset(tt.filters, tt.keyset)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLKeysetCursor(t *testing.T) {
+	lastCreatedAt := time.Date(2021, 4, 28, 10, 0, 0, 0, time.UTC)
+	keyset := models.Keyset{Column: "created_at", TieBreaker: "id", Order: models.Desc, Limit: 10}
+	filters := models.Fields{{Name: "is_active", Value: true}}
+
+	tests := []struct {
+		name      string
+		cursor    string
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "first page",
+			cursor:    "",
+			wantQuery: "WHERE is_active = $1 ORDER BY created_at DESC, id DESC LIMIT 10",
+			wantArgs:  []interface{}{true},
+		},
+		{
+			name:      "decoded cursor",
+			cursor:    models.EncodeCursor([]interface{}{lastCreatedAt, 42}),
+			wantQuery: "WHERE (is_active = $1) AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT 10",
+			wantArgs:  []interface{}{true, lastCreatedAt, 42},
+		},
+		{
+			name:      "cursor without a value for each column",
+			cursor:    models.EncodeCursor([]interface{}{lastCreatedAt}),
+			wantQuery: ErrInvalidCursor,
+			wantArgs:  nil,
+		},
+		{
+			name:      "malformed cursor",
+			cursor:    "not a cursor",
+			wantQuery: ErrMalformedCursor,
+			wantArgs:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLKeysetCursor(filters, keyset, tt.cursor)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+)
+
+// WarningCode identifies a pattern of a specification that is valid but known to cause bugs
+type WarningCode string
+
+// WarningCodes
+const (
+	// WarningUnstablePagination is a pagination without a sort by the IDColumn of the Config,
+	// so the rows of the pages can repeat or be lost between queries
+	WarningUnstablePagination WarningCode = "UNSTABLE_PAGINATION"
+
+	// WarningUngroupedOr is an OR mixed with AND outside of a group, so the OR
+	// can escape the conditions that were meant to apply to it
+	WarningUngroupedOr WarningCode = "UNGROUPED_OR"
+
+	// WarningEmptyIN is an IN or NOT IN without values
+	WarningEmptyIN WarningCode = "EMPTY_IN"
+
+	// WarningReversedBetween is a BETWEEN whose from value is greater than its to value,
+	// so it doesn't match any row
+	WarningReversedBetween WarningCode = "REVERSED_BETWEEN"
+)
+
+// Warning is a non-fatal problem of a specification found by LintSpec
+type Warning struct {
+	Code    WarningCode
+	Field   string
+	Message string
+}
+
+// LintSpec returns the warnings of the patterns of the specification known to cause bugs,
+// the specification is still valid so the warnings are meant for the developer before the runtime
+func LintSpec(spec models.FieldsSpecification) []Warning {
+	var warnings []Warning
+
+	if warning, ok := lintPagination(spec.Sorts, spec.Pagination); ok {
+		warnings = append(warnings, warning)
+	}
+
+	if warning, ok := lintUngroupedOr(spec.Filters); ok {
+		warnings = append(warnings, warning)
+	}
+
+	for _, field := range spec.Filters {
+		if field.Operator == models.In || field.Operator == models.NotIn {
+			if err := (models.Fields{field}).ValidateInNotEmpty(); err != nil {
+				warnings = append(warnings, Warning{Code: WarningEmptyIN, Field: field.Name, Message: err.Error()})
+			}
+		}
+
+		// BETWEEN SYMMETRIC sorts its bounds, so they are never reversed
+		if field.IsBetween() && errors.Is(field.ValidateFromAndToValues(), models.ErrFromIsGreaterThanTo) {
+			warnings = append(warnings, Warning{
+				Code:    WarningReversedBetween,
+				Field:   field.Name,
+				Message: fmt.Sprintf("the from value %v of the field %s is greater than the to value %v", field.FromValue, field.Name, field.ToValue),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// lintPagination returns a warning if the pagination is not sorted by the IDColumn of the Config
+func lintPagination(sorts models.SortFields, pag models.Pagination) (Warning, bool) {
+	if pag.IsZero() {
+		return Warning{}, false
+	}
+
+	for _, sort := range sorts {
+		if strings.EqualFold(sort.Name, config.IDColumn) {
+			return Warning{}, false
+		}
+	}
+
+	return Warning{
+		Code:    WarningUnstablePagination,
+		Message: fmt.Sprintf("the pagination must be sorted by %s to be deterministic", config.IDColumn),
+	}, true
+}
+
+// lintUngroupedOr returns a warning if the filters chain OR and AND outside of the groups
+func lintUngroupedOr(fields models.Fields) (Warning, bool) {
+	nGroups := 0
+	hasAnd := false
+	orField := ""
+
+	for key, field := range fields {
+		nGroups += field.GroupOpens()
+		if nGroups -= field.GroupCloses(); nGroups < 0 {
+			nGroups = 0
+		}
+
+		// the chaining key of the last field is not written
+		if nGroups > 0 || key == len(fields)-1 {
+			continue
+		}
+
+		if field.ChainingKey == models.Or {
+			if orField == "" {
+				orField = field.Name
+			}
+			continue
+		}
+		hasAnd = true
+	}
+
+	if orField == "" || !hasAnd {
+		return Warning{}, false
+	}
+
+	return Warning{
+		Code:    WarningUngroupedOr,
+		Field:   orField,
+		Message: fmt.Sprintf("the OR of the field %s is mixed with AND outside of a group", orField),
+	}, true
+}
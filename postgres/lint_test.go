@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  models.FieldsSpecification
+		codes []WarningCode
+	}{
+		{
+			name: "valid specification",
+			spec: models.FieldsSpecification{
+				Filters: models.Fields{
+					{Name: "employer_id", Value: 1},
+					{Name: "status", Value: "active", ChainingKey: models.Or, GroupOpen: true},
+					{Name: "status", Value: "pending", GroupClose: true},
+					{Name: "id", Operator: models.In, Value: []int{1, 2}},
+					{Name: "salary", Operator: models.Between, FromValue: 1000, ToValue: 5000},
+				},
+				Sorts:      models.SortFields{{Name: "created_at", Order: models.Desc}, {Name: "ID"}},
+				Pagination: models.Pagination{Page: 1, Limit: 10},
+			},
+			codes: nil,
+		},
+		{
+			name: "pagination without sort",
+			spec: models.FieldsSpecification{
+				Pagination: models.Pagination{Page: 1, Limit: 10},
+			},
+			codes: []WarningCode{WarningUnstablePagination},
+		},
+		{
+			name: "pagination without a sort by id",
+			spec: models.FieldsSpecification{
+				Sorts:      models.SortFields{{Name: "created_at"}},
+				Pagination: models.Pagination{Page: 2, Limit: 10},
+			},
+			codes: []WarningCode{WarningUnstablePagination},
+		},
+		{
+			name: "sort without pagination",
+			spec: models.FieldsSpecification{
+				Sorts: models.SortFields{{Name: "created_at"}},
+			},
+			codes: nil,
+		},
+		{
+			name: "OR mixed with AND",
+			spec: models.FieldsSpecification{
+				Filters: models.Fields{
+					{Name: "employer_id", Value: 1},
+					{Name: "status", Value: "active", ChainingKey: models.Or},
+					{Name: "status", Value: "pending"},
+				},
+			},
+			codes: []WarningCode{WarningUngroupedOr},
+		},
+		{
+			name: "only OR",
+			spec: models.FieldsSpecification{
+				Filters: models.Fields{
+					{Name: "status", Value: "active", ChainingKey: models.Or},
+					{Name: "status", Value: "pending"},
+				},
+			},
+			codes: nil,
+		},
+		{
+			name: "empty IN",
+			spec: models.FieldsSpecification{
+				Filters: models.Fields{
+					{Name: "id", Operator: models.In, Value: []int{}},
+				},
+			},
+			codes: []WarningCode{WarningEmptyIN},
+		},
+		{
+			name: "reversed BETWEEN",
+			spec: models.FieldsSpecification{
+				Filters: models.Fields{
+					{Name: "begins_at", Operator: models.Between, FromValue: parseToDate(2020, 1, 1), ToValue: parseToDate(2010, 5, 3)},
+					{Name: "salary", Operator: models.NotBetween, FromValue: 5000, ToValue: 1000},
+					{Name: "bonus", Operator: models.BetweenSymmetric, FromValue: 500, ToValue: 100},
+				},
+			},
+			codes: []WarningCode{WarningReversedBetween, WarningReversedBetween},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var codes []WarningCode
+			for _, warning := range LintSpec(tt.spec) {
+				codes = append(codes, warning.Code)
+			}
+			assert.Equal(t, tt.codes, codes)
+		})
+	}
+}
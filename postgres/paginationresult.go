@@ -0,0 +1,56 @@
+package postgres
+
+import "github.com/AJRDRGZ/db-query-builder/models"
+
+// PaginationResult contains the information of a page of a paginated query
+type PaginationResult struct {
+	Page       uint `json:"page"`
+	Limit      uint `json:"limit"`
+	Total      uint `json:"total"`
+	TotalPages uint `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+// BuildPaginationResult returns the PaginationResult of the page of the pagination with total rows,
+// the page and the limit are normalized as BuildSQLPagination does, with an Offset the page
+// is the one that contains the first row of the offset. A zero pagination selects all the rows,
+// so it is one page with all of them
+func BuildPaginationResult(total uint, pag models.Pagination) PaginationResult {
+	if pag.IsZero() {
+		result := PaginationResult{Page: 1, Limit: total, Total: total}
+		if total > 0 {
+			result.TotalPages = 1
+		}
+
+		return result
+	}
+
+	limit, offset := limitAndOffset(pag)
+
+	// a zero limit (DefaultMaxLimit = 0) has no rows by page
+	if limit == 0 {
+		page := pag.Page
+		if page == 0 {
+			page = 1
+		}
+
+		return PaginationResult{Page: page, Total: total, HasPrev: page > 1}
+	}
+
+	page := offset/limit + 1
+
+	totalPages := total / limit
+	if total%limit > 0 {
+		totalPages++
+	}
+
+	return PaginationResult{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
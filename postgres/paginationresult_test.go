@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPaginationResult(t *testing.T) {
+	tests := []struct {
+		name  string
+		total uint
+		pag   models.Pagination
+		want  PaginationResult
+	}{
+		{
+			name:  "without rows",
+			total: 0,
+			pag:   models.Pagination{Page: 1},
+			want:  PaginationResult{Page: 1, Limit: 20, Total: 0, TotalPages: 0},
+		},
+		{
+			name:  "zero pagination",
+			total: 45,
+			pag:   models.Pagination{},
+			want:  PaginationResult{Page: 1, Limit: 45, Total: 45, TotalPages: 1},
+		},
+		{
+			name:  "zero pagination without rows",
+			total: 0,
+			pag:   models.Pagination{},
+			want:  PaginationResult{Page: 1, Limit: 0, Total: 0, TotalPages: 0},
+		},
+		{
+			name:  "first page",
+			total: 23,
+			pag:   models.Pagination{Page: 1, Limit: 10},
+			want:  PaginationResult{Page: 1, Limit: 10, Total: 23, TotalPages: 3, HasNext: true},
+		},
+		{
+			name:  "partial last page",
+			total: 23,
+			pag:   models.Pagination{Page: 3, Limit: 10},
+			want:  PaginationResult{Page: 3, Limit: 10, Total: 23, TotalPages: 3, HasPrev: true},
+		},
+		{
+			name:  "page beyond the last",
+			total: 23,
+			pag:   models.Pagination{Page: 7, Limit: 10},
+			want:  PaginationResult{Page: 7, Limit: 10, Total: 23, TotalPages: 3, HasPrev: true},
+		},
+		{
+			name:  "limit greater than the max limit",
+			total: 50,
+			pag:   models.Pagination{Page: 2, Limit: 100, MaxLimit: 25},
+			want:  PaginationResult{Page: 2, Limit: 25, Total: 50, TotalPages: 2, HasPrev: true},
+		},
+		{
+			name:  "explicit offset",
+			total: 23,
+			pag:   models.Pagination{Limit: 5, Offset: 7},
+			want:  PaginationResult{Page: 2, Limit: 5, Total: 23, TotalPages: 5, HasNext: true, HasPrev: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BuildPaginationResult(tt.total, tt.pag))
+		})
+	}
+}
+
+func TestBuildPaginationResult_ZeroDefaultMaxLimit(t *testing.T) {
+	DefaultMaxLimit = 0
+	defer func() { DefaultMaxLimit = 20 }()
+
+	assert.Equal(t, PaginationResult{Page: 2, Total: 45, HasPrev: true}, BuildPaginationResult(45, models.Pagination{Page: 2}))
+	assert.Equal(t, PaginationResult{Page: 1, Total: 45}, BuildPaginationResult(45, models.Pagination{Offset: 10}))
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+)
+
+// partialIndexFilters are the predicates of the partial indexes of each table
+var partialIndexFilters = map[string]models.Fields{}
+
+// SetPartialIndexFilters sets the predicates IS NULL or IS NOT NULL of the partial index of the table,
+// so they are always added to the filters of the table by the builders that receive the table
+// (QueryBuilder, BuildSQLCount, BuildSQLExists, BuildSQLFacetCount, BuildSQLCountByStatus,
+// BuildSQLAggregateQuery and BuildSQLDeleteBatch) and the planner can use the index,
+// ej: SetPartialIndexFilters("users", models.Fields{{Name: "deleted_at", Operator: models.IsNull}}).
+// Empty predicates remove the predicates of the table. It must be called at the startup
+func SetPartialIndexFilters(table string, predicates models.Fields) error {
+	for _, predicate := range predicates {
+		if predicate.Operator != models.IsNull && predicate.Operator != models.IsNotNull {
+			return fmt.Errorf("%w: the operator %s of the field %s", models.ErrInvalidPartialIndexFilter, predicate.Operator, predicate.Name)
+		}
+	}
+
+	if predicates.IsEmpty() {
+		delete(partialIndexFilters, table)
+		return nil
+	}
+
+	// the predicates are always chained with AND
+	filters := append(models.Fields{}, predicates...)
+	for i := range filters {
+		filters[i].ChainingKey = models.And
+	}
+
+	partialIndexFilters[table] = filters
+	return nil
+}
+
+// WithPartialIndexFilters returns the filters followed by the predicates of the partial index of the table,
+// both sides are grouped and joined with AND: (filters) AND (predicates), see models.Fields.AndGroup.
+// The builders don't use it, they wrap the rendered filters: WHERE (filters) AND predicates
+func WithPartialIndexFilters(table string, filters models.Fields) models.Fields {
+	predicates, ok := partialIndexFilters[table]
+	if !ok {
+		return filters
+	}
+
+	return filters.AndGroup(predicates)
+}
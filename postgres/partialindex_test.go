@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AJRDRGZ/db-query-builder/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPartialIndexFilters(t *testing.T) {
+	err := SetPartialIndexFilters("users", models.Fields{
+		{Name: "deleted_at", Operator: models.IsNull},
+		{Name: "email", Operator: models.IsNotNull},
+	})
+	assert.NoError(t, err)
+	defer SetPartialIndexFilters("users", nil)
+
+	tests := []struct {
+		name      string
+		table     string
+		filters   models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:  "filters chained with OR",
+			table: "users",
+			filters: models.Fields{
+				{Name: "name", Value: "Alejandro", ChainingKey: models.Or},
+				{Name: "name", Value: "Maria"},
+			},
+			wantQuery: "WHERE (name = $1 OR name = $2) AND (deleted_at IS NULL AND email IS NOT NULL)",
+			wantArgs:  []interface{}{"Alejandro", "Maria"},
+		},
+		{
+			name:      "without filters",
+			table:     "users",
+			filters:   nil,
+			wantQuery: "WHERE deleted_at IS NULL AND email IS NOT NULL",
+			wantArgs:  nil,
+		},
+		{
+			name:      "table without partial index",
+			table:     "contracts",
+			filters:   models.Fields{{Name: "status", Value: "active"}},
+			wantQuery: "WHERE status = $1",
+			wantArgs:  []interface{}{"active"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := BuildSQLWhere(WithPartialIndexFilters(tt.table, tt.filters))
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestQueryBuilder_Build_PartialIndexFilters(t *testing.T) {
+	err := SetPartialIndexFilters("users", models.Fields{{Name: "deleted_at", Operator: models.IsNull}})
+	assert.NoError(t, err)
+	defer SetPartialIndexFilters("users", nil)
+
+	query, args := new(QueryBuilder).
+		Select("users", []string{"id", "name"}).
+		Where(models.Fields{{Name: "name", Value: "Alejandro"}}).
+		Build()
+
+	assert.Equal(t, "SELECT id, name FROM users WHERE (name = $1) AND deleted_at IS NULL", query)
+	assert.Equal(t, []interface{}{"Alejandro"}, args)
+}
+
+func TestPartialIndexFilters_TableBuilders(t *testing.T) {
+	err := SetPartialIndexFilters("users", models.Fields{{Name: "deleted_at", Operator: models.IsNull}})
+	assert.NoError(t, err)
+	defer SetPartialIndexFilters("users", nil)
+
+	filters := models.Fields{
+		{Name: "name", Value: "Alejandro", ChainingKey: models.Or},
+		{Name: "is_admin", Value: true},
+	}
+	where := "WHERE (name = $1 OR is_admin = $2) AND deleted_at IS NULL"
+	wantArgs := []interface{}{"Alejandro", true}
+
+	query, args := BuildSQLCount("users", filters)
+	assert.Equal(t, "SELECT COUNT(*) FROM users "+where, query)
+	assert.Equal(t, wantArgs, args)
+
+	query, args = BuildSQLExists("users", filters)
+	assert.Equal(t, "SELECT EXISTS(SELECT 1 FROM users "+where+")", query)
+	assert.Equal(t, wantArgs, args)
+
+	query, args = BuildSQLFacetCount("users", "role", filters)
+	assert.Equal(t, "SELECT role, COUNT(*) FROM users "+where+" GROUP BY role", query)
+	assert.Equal(t, wantArgs, args)
+
+	query, args = BuildSQLDeleteBatch("users", filters, 100)
+	assert.Equal(t, "DELETE FROM users WHERE id IN (SELECT id FROM users "+where+" LIMIT 100)", query)
+	assert.Equal(t, wantArgs, args)
+
+	query, args = BuildSQLAggregateQuery("users", []models.Column{{Name: "role"}}, filters, []string{"role"}, nil, nil, models.Pagination{})
+	assert.Equal(t, "SELECT role FROM users "+where+" GROUP BY role", query)
+	assert.Equal(t, wantArgs, args)
+
+	query, args = BuildSQLCount("users", nil)
+	assert.Equal(t, "SELECT COUNT(*) FROM users WHERE deleted_at IS NULL", query)
+	assert.Nil(t, args)
+}
+
+func TestPartialIndexFilters_GroupedFilters(t *testing.T) {
+	err := SetPartialIndexFilters("users", models.Fields{{Name: "deleted_at", Operator: models.IsNull}})
+	assert.NoError(t, err)
+	defer SetPartialIndexFilters("users", nil)
+
+	query, args := new(QueryBuilder).
+		Select("users", []string{"id"}).
+		Where(models.Fields{
+			{Name: "a", Value: 1, ChainingKey: models.Or, GroupOpen: true},
+			{Name: "b", Value: 2, ChainingKey: models.Or, GroupClose: true},
+			{Name: "c", Value: 3, ChainingKey: models.Or, GroupOpen: true},
+			{Name: "d", Value: 4, GroupClose: true},
+		}).
+		Build()
+
+	assert.Equal(t, "SELECT id FROM users WHERE ((a = $1 OR b = $2) OR (c = $3 OR d = $4)) AND deleted_at IS NULL", query)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+
+	query, args = BuildSQLCount("users", models.HalfOpenRange("created_at", "2021-01-01", "2021-02-01"))
+	assert.Equal(t, "SELECT COUNT(*) FROM users WHERE ((created_at >= $1 AND created_at < $2)) AND deleted_at IS NULL", query)
+	assert.Equal(t, []interface{}{"2021-01-01", "2021-02-01"}, args)
+}
+
+func TestSetPartialIndexFilters_InvalidOperator(t *testing.T) {
+	err := SetPartialIndexFilters("users", models.Fields{{Name: "status", Operator: models.Equals, Value: "active"}})
+	assert.True(t, errors.Is(err, models.ErrInvalidPartialIndexFilter))
+
+	assert.Equal(t, models.Fields{{Name: "name"}}, WithPartialIndexFilters("users", models.Fields{{Name: "name"}}))
+}
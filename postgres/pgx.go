@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// PgxArgs converts the arguments returned by the builders to values
+// that pgx accepts as positional parameters.
+//
+// Differences with lib/pq:
+//   - slices are kept as Go slices because pgx encodes them as postgres arrays,
+//     lib/pq needs them wrapped with pq.Array
+//   - time.Time is kept as is because pgx encodes it as timestamptz
+//   - driver.Valuer values (sql.NullString, pq.Array, ...) are resolved with its Value method
+//   - the rest of values are converted with driver.DefaultParameterConverter (ej: int to int64)
+func PgxArgs(args []interface{}) ([]driver.Value, error) {
+	if args == nil {
+		return nil, nil
+	}
+
+	values := make([]driver.Value, 0, len(args))
+	for k, arg := range args {
+		value, err := pgxValue(arg)
+		if err != nil {
+			return nil, fmt.Errorf("psql: could not convert the argument $%d: %w", k+1, err)
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func pgxValue(arg interface{}) (driver.Value, error) {
+	switch v := arg.(type) {
+	case nil:
+		return nil, nil
+	case driver.Valuer:
+		return v.Value()
+	case time.Time, []byte:
+		return v, nil
+	}
+
+	kind := reflect.TypeOf(arg).Kind()
+	if kind == reflect.Slice || kind == reflect.Array {
+		return arg, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(arg)
+}
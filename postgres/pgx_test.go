@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPgxArgs(t *testing.T) {
+	fakeDate := time.Date(2021, 4, 28, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		args    []interface{}
+		want    []driver.Value
+		wantErr bool
+	}{
+		{
+			name: "without args",
+			args: nil,
+			want: nil,
+		},
+		{
+			name: "time args",
+			args: []interface{}{fakeDate, sql.NullTime{Time: fakeDate, Valid: true}, sql.NullTime{}},
+			want: []driver.Value{fakeDate, fakeDate, nil},
+		},
+		{
+			name: "slice args",
+			args: []interface{}{[]int{1, 2}, []string{"COL", "COP"}, pq.Array([]int64{3, 4})},
+			want: []driver.Value{[]int{1, 2}, []string{"COL", "COP"}, "{3,4}"},
+		},
+		{
+			name: "scalar args",
+			args: []interface{}{30, uint(7), "Go", true, nil},
+			want: []driver.Value{int64(30), int64(7), "Go", true, nil},
+		},
+		{
+			name:    "unsupported args",
+			args:    []interface{}{struct{}{}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PgxArgs(tt.args)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,8 +3,12 @@ package postgres
 import (
 	"bytes"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/lib/pq"
@@ -12,7 +16,46 @@ import (
 	"github.com/AJRDRGZ/db-query-builder/models"
 )
 
-const ErrFieldsAreEmpty = "FAILED! YOU NEED TO SEND FIELDS"
+const (
+	ErrFieldsAreEmpty       = "FAILED! YOU NEED TO SEND FIELDS"
+	ErrRowsAreEmpty         = "FAILED! YOU NEED TO SEND ROWS"
+	ErrRowsColumnsMissMatch = "FAILED! ALL THE ROWS MUST HAVE A VALUE FOR EACH COLUMN"
+	ErrInvalidIdentifier    = "FAILED! THE IDENTIFIER IS NOT VALID"
+	ErrInvalidGroupingMode  = "FAILED! THE GROUPING MODE IS NOT VALID"
+	ErrInvalidRowCount      = "FAILED! THE ROW COUNT MUST BE GREATER THAN ZERO"
+	ErrInvalidLockMode      = "FAILED! THE LOCK MODE IS NOT VALID"
+	ErrInvalidPagination    = "FAILED! THE PAGINATION IS NOT VALID"
+)
+
+// placeholderRegexp matches a placeholder of postgres, ej: $1
+var placeholderRegexp = regexp.MustCompile(`\$([0-9]+)`)
+
+// identifierRegexp matches a postgres identifier without quotes, optionally qualified by the schema
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// InAnyThreshold is the maximum number of values that BuildSQLWhere writes inline in an IN,
+// above it the IN is switched to `name = ANY($1)` with the values wrapped as a
+// postgres array (pq.Array), this avoids huge queries that bust the query-plan caches
+var InAnyThreshold = 100
+
+// InlineStringIN allows BuildSQLWhere to write inline the values of an IN of strings.
+// By default the numeric values are written inline because they have no risk of injection
+// and help the planner, but the strings are parameterized: code IN ($1, $2)
+var InlineStringIN = false
+
+// ParameterizeIN allows BuildSQLWhere to bind every value of an IN as a parameter,
+// including the numeric values, ej: id IN ($1, $2, $3), so the query is the same for any
+// value and every value is protected as in the rest of the WHERE.
+// It is false by default because it changes the arguments returned by BuildSQLWhere
+var ParameterizeIN = false
+
+// DefaultMaxLimit is the limit of the pagination when the MaxLimit of the pagination is not set,
+// it allows to set the cap once at the startup instead of in every pagination
+var DefaultMaxLimit uint = 20
+
+// StrictPagination allows BuildSQLPagination to validate the pagination,
+// so a limit greater than the MaxLimit is an error instead of being clamped
+var StrictPagination = false
 
 // Constraints is a map with a key with the constraint name and contains a value as error
 type Constraints map[string]error
@@ -32,6 +75,69 @@ func CheckConstraint(constraints Constraints, err error) error {
 	return constraintErr
 }
 
+// ParseConstraintName parses the name of a constraint that follows the default naming
+// convention of postgres: <table>_<column>_<kind> or <table>_pkey for primary keys,
+// ej: orders_user_id_fkey returns "orders", "user_id", "fkey".
+// It is a best-effort parse, the table is the text before the first underscore and the column
+// is the rest, because the columns usually have underscores (user_id) and the tables usually don't.
+// The tables with underscores are split wrong, ej: order_items_product_id_fkey returns
+// "order", "items_product_id", "fkey", so use ParseConstraintNameOfTable for them.
+// It returns zero values if the name doesn't match
+func ParseConstraintName(name string) (table, column, kind string) {
+	if strings.HasSuffix(name, "_pkey") {
+		table = strings.TrimSuffix(name, "_pkey")
+		if table == "" {
+			return "", "", ""
+		}
+
+		return table, "", "pkey"
+	}
+
+	tableColumn, kind := trimConstraintKind(name)
+	if kind == "" {
+		return "", "", ""
+	}
+
+	parts := strings.SplitN(tableColumn, "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", ""
+	}
+
+	return parts[0], parts[1], kind
+}
+
+// ParseConstraintNameOfTable parses the name of a constraint of the table that follows the default
+// naming convention of postgres, ej: order_items_product_id_fkey of the table order_items returns
+// "product_id", "fkey". It returns zero values if the name doesn't match
+func ParseConstraintNameOfTable(name, table string) (column, kind string) {
+	if table == "" || !strings.HasPrefix(name, table+"_") {
+		return "", ""
+	}
+
+	if name == table+"_pkey" {
+		return "", "pkey"
+	}
+
+	column, kind = trimConstraintKind(strings.TrimPrefix(name, table+"_"))
+	if column == "" || kind == "" {
+		return "", ""
+	}
+
+	return column, kind
+}
+
+// trimConstraintKind returns the name without the suffix of the kind of the constraint and the kind,
+// if the name doesn't have a suffix it returns an empty kind
+func trimConstraintKind(name string) (string, string) {
+	for _, kind := range []string{"fkey", "key", "check", "excl"} {
+		if strings.HasSuffix(name, "_"+kind) {
+			return strings.TrimSuffix(name, "_"+kind), kind
+		}
+	}
+
+	return name, ""
+}
+
 // RowScanner utilidad para leer los registros de un Query
 type RowScanner interface {
 	Scan(dest ...interface{}) error
@@ -58,23 +164,92 @@ func ExecAffectingOneRow(stmt *sql.Stmt, args ...interface{}) error {
 
 // BuildSQLInsert builds a query INSERT of postgres
 func BuildSQLInsert(table string, fields []string) string {
+	return BuildSQLInsertReturning(table, fields, nil)
+}
+
+// BuildSQLInsertReturning builds a query INSERT of postgres with the returning columns,
+// if returning is empty it returns `id, created_at`
+func BuildSQLInsertReturning(table string, fields []string, returning []string) string {
 	if len(fields) == 0 {
 		return ErrFieldsAreEmpty
 	}
+	if len(returning) == 0 {
+		returning = defaultReturning()
+	}
+
+	return fmt.Sprintf("%s RETURNING %s", buildSQLInsertValues(table, fields), strings.Join(returning, ", "))
+}
 
+// buildSQLInsertValues builds a query INSERT of postgres without RETURNING
+func buildSQLInsertValues(table string, fields []string) string {
 	args := bytes.Buffer{}
 	values := bytes.Buffer{}
 
 	for k, v := range fields {
 		args.WriteString(v)
 		args.WriteString(", ")
-		values.WriteString(fmt.Sprintf("$%d, ", k+1))
+		values.WriteString(placeholder(k+1) + ", ")
 	}
 
 	args.Truncate(args.Len() - 2)
 	values.Truncate(values.Len() - 2)
 
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING id, created_at", table, args.String(), values.String())
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, args.String(), values.String())
+}
+
+// BuildSQLBulkInsert builds a query INSERT of postgres with rowCount rows,
+// ej: INSERT INTO t (a, b) VALUES ($1, $2), ($3, $4) RETURNING id, created_at.
+// The args must be flattened in row-major order: all the fields of the first row,
+// then all the fields of the second row, and so on
+func BuildSQLBulkInsert(table string, fields []string, rowCount int) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
+	}
+	if rowCount < 1 {
+		return ErrInvalidRowCount
+	}
+
+	rows := make([]string, 0, rowCount)
+	values := make([]string, len(fields))
+	k := 0
+	for i := 0; i < rowCount; i++ {
+		for j := range fields {
+			k++
+			values[j] = placeholder(k)
+		}
+		rows = append(rows, fmt.Sprintf("(%s)", strings.Join(values, ", ")))
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s RETURNING %s",
+		table, strings.Join(fields, ", "), strings.Join(rows, ", "), strings.Join(defaultReturning(), ", "))
+}
+
+// MaxParams is the maximum number of parameters that postgres allows in a statement
+const MaxParams = 65535
+
+// ChunkBulkInsert returns the ranges of rows [start, end) to insert by statement so that
+// no statement exceeds maxParams parameters, if maxParams is lower than 1 it uses MaxParams.
+// Each range is built with BuildSQLBulkInsert(table, fields, end-start) and the args of its rows.
+// It returns nil if there are no fields or rows, or if a row alone exceeds maxParams
+func ChunkBulkInsert(fields []string, rowCount, maxParams int) [][2]int {
+	if maxParams < 1 {
+		maxParams = MaxParams
+	}
+	if len(fields) == 0 || rowCount < 1 || len(fields) > maxParams {
+		return nil
+	}
+
+	rowsByChunk := maxParams / len(fields)
+	chunks := make([][2]int, 0, (rowCount+rowsByChunk-1)/rowsByChunk)
+	for start := 0; start < rowCount; start += rowsByChunk {
+		end := start + rowsByChunk
+		if end > rowCount {
+			end = rowCount
+		}
+		chunks = append(chunks, [2]int{start, end})
+	}
+
+	return chunks
 }
 
 // BuildSQLInsertWithID builds a query INSERT of postgres allowing to send the ID
@@ -87,34 +262,57 @@ func BuildSQLInsertWithID(table string, fields []string) string {
 	values := bytes.Buffer{}
 	k := 1
 
-	args.WriteString("id, ")
-	values.WriteString(fmt.Sprintf("$%d, ", k))
+	args.WriteString(config.IDColumn + ", ")
+	values.WriteString(placeholder(k) + ", ")
 
 	for _, v := range fields {
 		k++
 		args.WriteString(v)
 		args.WriteString(", ")
-		values.WriteString(fmt.Sprintf("$%d, ", k))
+		values.WriteString(placeholder(k) + ", ")
 	}
 
 	args.Truncate(args.Len() - 2)
 	values.Truncate(values.Len() - 2)
 
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING created_at", table, args.String(), values.String())
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", table, args.String(), values.String(), config.CreatedAtColumn)
 }
 
 // BuildSQLUpdateByID builds a query UPDATE of postgres
 func BuildSQLUpdateByID(table string, fields []string) string {
+	return BuildSQLUpdateByColumn(table, fields, config.IDColumn)
+}
+
+// BuildSQLUpdateByColumn builds a query UPDATE of postgres using the idColumn as key,
+// if idColumn is empty it uses the id column of the Config
+func BuildSQLUpdateByColumn(table string, fields []string, idColumn string) string {
 	if len(fields) == 0 {
 		return ErrFieldsAreEmpty
 	}
 
+	if idColumn == "" {
+		idColumn = config.IDColumn
+	}
+
+	set, nextParam := BuildSQLSet(fields, 1)
+
+	return fmt.Sprintf("UPDATE %s SET %s, %s = now() WHERE %s = %s", table, set, config.UpdatedAtColumn, idColumn, placeholder(nextParam))
+}
+
+// BuildSQLSet builds the SET list of an UPDATE of postgres starting the placeholders in startParam,
+// and it returns the next param index
+func BuildSQLSet(fields []string, startParam int) (string, int) {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty, startParam
+	}
+
 	args := bytes.Buffer{}
 	for k, v := range fields {
-		args.WriteString(fmt.Sprintf("%s = $%d, ", v, k+1))
+		args.WriteString(fmt.Sprintf("%s = %s, ", v, placeholder(startParam+k)))
 	}
+	args.Truncate(args.Len() - 2)
 
-	return fmt.Sprintf("UPDATE %s SET %supdated_at = now() WHERE id = $%d", table, args.String(), len(fields)+1)
+	return args.String(), startParam + len(fields)
 }
 
 // BuildSQLSelect builds a query SELECT of postgres
@@ -128,11 +326,13 @@ func BuildSQLSelect(table string, fields []string) string {
 		args.WriteString(fmt.Sprintf("%s, ", v))
 	}
 
-	return fmt.Sprintf("SELECT id, %screated_at, updated_at FROM %s", args.String(), table)
+	return fmt.Sprintf("SELECT %s, %s%s, %s FROM %s",
+		config.IDColumn, args.String(), config.CreatedAtColumn, config.UpdatedAtColumn, table)
 }
 
-// BuildSQLSelectFields builds a query SELECT of postgres
-func BuildSQLSelectFields(table string, fields []string) string {
+// BuildSQLSelectWithDeletedAt builds a query SELECT of postgres including the
+// deleted_at column of the soft deletes after created_at and updated_at
+func BuildSQLSelectWithDeletedAt(table string, fields []string) string {
 	if len(fields) == 0 {
 		return ErrFieldsAreEmpty
 	}
@@ -141,264 +341,1361 @@ func BuildSQLSelectFields(table string, fields []string) string {
 	for _, v := range fields {
 		args.WriteString(fmt.Sprintf("%s, ", v))
 	}
-	args.Truncate(args.Len() - 2)
 
-	return fmt.Sprintf("SELECT %s FROM %s", args.String(), table)
+	return fmt.Sprintf("SELECT %s, %s%s, %s, deleted_at FROM %s",
+		config.IDColumn, args.String(), config.CreatedAtColumn, config.UpdatedAtColumn, table)
 }
 
-// BuildSQLWhere builds and returns a query WHERE of postgres and its arguments
-func BuildSQLWhere(fields models.Fields) (string, []interface{}) {
-	if fields.IsEmpty() {
-		return "", nil
+// BuildSQLSelectActive builds a query SELECT of postgres of the rows that are not soft deleted
+func BuildSQLSelectActive(table string, fields []string) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
 	}
 
-	query := bytes.Buffer{}
-	query.WriteString("WHERE ")
-	length := len(fields)
-	lastFieldIndex := length - 1
-	nGroups := 0
-	var args []interface{}
-
-	paramSequence := 1
-	for key, field := range fields {
-		setDefaultValuesField(&field)
-
-		if field.GroupOpen {
-			nGroups++
-		}
+	return BuildSQLSelect(table, fields) + " WHERE deleted_at IS NULL"
+}
 
-		switch field.Operator {
-		case models.In:
-			query.WriteString(BuildIN(field))
-		case models.IsNull, models.IsNotNull:
-			query.WriteString(fmt.Sprintf("%s %s", strings.ToLower(field.Name), field.Operator))
-		case models.Between:
-			// TODO: improve this function to return an error instead of string
-			if err := field.ValidateFromAndToValues(); err != nil {
-				return err.Error(), nil
-			}
+// BuildSQLSelectWithTotalCount builds a query SELECT of postgres adding the column
+// `COUNT(*) OVER() AS total_count`, so a paginated query returns the page and the total
+// of rows that match the filters in one query.
+// The tradeoff vs a separate count query: the total is repeated in every row and
+// the page returns no rows (so no total) when the offset is out of range
+func BuildSQLSelectWithTotalCount(table string, fields []string) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
+	}
 
-			query.WriteString(fmt.Sprintf("%s %s $%d AND $%d",
-				strings.ToLower(field.Name),
-				field.Operator,
-				paramSequence,
-				paramSequence+1,
-			))
+	args := bytes.Buffer{}
+	for _, v := range fields {
+		args.WriteString(fmt.Sprintf("%s, ", v))
+	}
 
-			// Increment paramSequence because `BETWEEN` has 2 params always
-			paramSequence++
-		default:
-			// if we need to compare against the column of other table
-			if field.IsValueFromTable {
-				query.WriteString(fmt.Sprintf("%s %s %s",
-					strings.ToLower(field.Name),
-					field.Operator,
-					strings.ToLower(field.NameValueFromTable),
-				))
+	return fmt.Sprintf("SELECT %s, %s%s, %s, COUNT(*) OVER() AS total_count FROM %s",
+		config.IDColumn, args.String(), config.CreatedAtColumn, config.UpdatedAtColumn, table)
+}
 
-				break
-			}
+// BuildSQLSelectDistinct builds a query SELECT DISTINCT of postgres
+func BuildSQLSelectDistinct(table string, fields []string) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
+	}
 
-			// if we compare against a value that we define
-			query.WriteString(fmt.Sprintf("%s %s $%d",
-				strings.ToLower(field.Name),
-				field.Operator,
-				paramSequence,
-			))
-		}
+	return strings.Replace(BuildSQLSelect(table, fields), "SELECT ", "SELECT DISTINCT ", 1)
+}
 
-		// Close the group
-		if (nGroups > 0) && field.GroupClose {
-			nGroups--
-			query.WriteString(")")
-		}
+// BuildSQLSelectDistinctOn builds a query SELECT DISTINCT ON of postgres that keeps
+// the first row of each group of distinctCols, ej: SELECT DISTINCT ON (a, b) id, ...
+// The ORDER BY of the query must begin with the distinctCols, see ValidateDistinctOnOrder
+func BuildSQLSelectDistinctOn(table string, distinctCols []string, fields []string) string {
+	if len(fields) == 0 || len(distinctCols) == 0 {
+		return ErrFieldsAreEmpty
+	}
 
-		// if exists still groups open, close them in the last field
-		if (nGroups > 0) && (key == lastFieldIndex) {
-			query.WriteString(strings.Repeat(")", nGroups))
+	for _, column := range distinctCols {
+		if !isValidIdentifier(column) {
+			return ErrInvalidIdentifier
 		}
+	}
 
-		// Add chainingKey (OR, AND) except in the last field
-		if key != lastFieldIndex {
-			query.WriteString(fmt.Sprintf(" %s ", field.ChainingKey))
-		}
+	return strings.Replace(BuildSQLSelect(table, fields), "SELECT ",
+		fmt.Sprintf("SELECT DISTINCT ON (%s) ", strings.Join(distinctCols, ", ")), 1)
+}
 
-		if field.Operator == models.In ||
-			field.Operator == models.IsNull ||
-			field.Operator == models.IsNotNull ||
-			field.IsValueFromTable {
+// ValidateDistinctOnOrder validates that the order begins with the distinctCols of a
+// SELECT DISTINCT ON, in any order between them, because postgres fails at runtime otherwise
+func ValidateDistinctOnOrder(distinctCols []string, order models.SortFields) error {
+	if len(order) < len(distinctCols) {
+		return models.ErrDistinctOnOrderMissMatch
+	}
 
-			continue
-		}
+	for _, sort := range order[:len(distinctCols)] {
+		setSortFieldAliases(&sort)
 
-		// Add arguments of the parameters when operator is different to "IN, IsNull, IsNotNull" or when IsValueFromTable is true
-		if field.Value != nil {
-			args = append(args, field.Value)
+		isDistinct := false
+		for _, column := range distinctCols {
+			if strings.EqualFold(column, sort.Name) {
+				isDistinct = true
+				break
+			}
 		}
-		if field.Operator == models.Between {
-			args = append(args, field.FromValue, field.ToValue)
+		if !isDistinct {
+			return models.ErrDistinctOnOrderMissMatch
 		}
-
-		paramSequence++
 	}
 
-	return query.String(), args
+	return nil
 }
 
-// BuildSQLOrderBy builds and returns a query ORDER BY of postgres and its arguments
-func BuildSQLOrderBy(sorts models.SortFields) string {
-	if sorts.IsEmpty() {
-		return ""
+// BuildSQLSelectByIDs builds a query SELECT of postgres filtered by a parameterized IN of ids
+// and sorted by the order, and it returns the ids as arguments, ej:
+// SELECT id, name, created_at, updated_at FROM users WHERE id IN ($1, $2) ORDER BY name ASC
+func BuildSQLSelectByIDs(table string, fields []string, ids []uint, order models.SortFields) (string, []interface{}) {
+	query := BuildSQLSelect(table, fields)
+	if query == ErrFieldsAreEmpty {
+		return query, nil
 	}
 
-	query := bytes.Buffer{}
-	query.WriteString("ORDER BY ")
+	p := &params{}
+	placeholders := make([]string, 0, len(ids))
+	for _, id := range ids {
+		placeholders = append(placeholders, p.bind(id))
+	}
 
-	for _, sort := range sorts {
-		setSortFieldOrder(&sort)
-		setSortFieldAliases(&sort)
-		query.WriteString(fmt.Sprintf("%s %s, ",
-			strings.ToLower(sort.Name),
-			sort.Order,
-		))
+	// if there aren't ids, nothing is selected
+	if len(placeholders) == 0 {
+		query += fmt.Sprintf(" WHERE %s = 0", config.IDColumn)
+	} else {
+		query += fmt.Sprintf(" WHERE %s IN (%s)", config.IDColumn, strings.Join(placeholders, ", "))
 	}
-	query.Truncate(query.Len() - 2)
 
-	return query.String()
+	if !order.IsEmpty() {
+		query += " " + BuildSQLOrderBy(order)
+	}
+
+	return query, p.args
 }
 
-// BuildSQLPagination builds and returns a query OFFSET LIMIT of postgres for pagination
-func BuildSQLPagination(pag models.Pagination) string {
-	if pag.Limit == 0 && pag.Page == 0 {
-		return ""
+// BuildSQLSelectFields builds a query SELECT of postgres
+func BuildSQLSelectFields(table string, fields []string) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
 	}
 
-	if pag.MaxLimit == 0 {
-		pag.MaxLimit = 20
+	args := bytes.Buffer{}
+	for _, v := range fields {
+		args.WriteString(fmt.Sprintf("%s, ", v))
 	}
+	args.Truncate(args.Len() - 2)
 
-	if pag.Limit == 0 || pag.Limit > pag.MaxLimit {
-		pag.Limit = pag.MaxLimit
+	return fmt.Sprintf("SELECT %s FROM %s", args.String(), table)
+}
+
+// BuildSQLSelectInto builds a query SELECT INTO of postgres that materializes the fields of
+// the sourceTable into the newTable
+func BuildSQLSelectInto(newTable, sourceTable string, fields []string) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
 	}
 
-	if pag.Page == 0 {
-		pag.Page = 1
+	if !isValidIdentifier(newTable) {
+		return ErrInvalidIdentifier
 	}
 
-	offset := pag.Page*pag.Limit - pag.Limit
+	return fmt.Sprintf("SELECT %s INTO %s FROM %s", strings.Join(fields, ", "), newTable, sourceTable)
+}
 
-	pagination := fmt.Sprintf("LIMIT %d OFFSET %d", pag.Limit, offset)
+// BuildSQLWhere builds and returns a query WHERE of postgres and its arguments,
+// if the fields are empty it returns an empty string and nil arguments because the WHERE is optional.
+// If the fields are not valid it returns the error as query, see BuildSQLWhereE
+func BuildSQLWhere(fields models.Fields) (string, []interface{}) {
+	query, args, err := BuildSQLWhereE(fields)
+	if errors.Is(err, models.ErrEmptyFields) {
+		return "", nil
+	}
+	if err != nil {
+		return err.Error(), nil
+	}
 
-	return pagination
+	return query, args
 }
 
-// BuildQueryArgsAndPagination builds and returns a query adding the filter + sort + pagination
-func BuildQueryArgsAndPagination(initialSQL string, filter models.Fields, sort models.SortFields, pag models.Pagination) (string, []interface{}) {
-	conditions, args := BuildSQLWhere(filter)
-	query := initialSQL + " " + conditions
+// BuildSQLWhereE builds and returns a query WHERE of postgres and its arguments,
+// it returns models.ErrEmptyFields if the fields are empty, or the error of the invalid field
+func BuildSQLWhereE(fields models.Fields) (string, []interface{}, error) {
+	if fields.IsEmpty() {
+		return "", nil, models.ErrEmptyFields
+	}
 
-	query += " " + BuildSQLOrderBy(sort)
-	query += " " + BuildSQLPagination(pag)
+	p := &params{}
+	query, err := buildSQLWhere(fields, p)
+	if err != nil {
+		return "", nil, err
+	}
 
-	return query, args
+	return query, p.args, nil
 }
 
-// BuildSQLDelete builds and returns a query with the DELETE statement
-func BuildSQLDelete(table string) string {
-	return fmt.Sprintf("DELETE FROM %s WHERE id = $1", table)
+// BuildSQLWhereReusingArgs builds and returns a query WHERE of postgres and its arguments,
+// the identical values share the same placeholder, ej: a search value compared against several columns
+// WHERE name ILIKE $1 OR description ILIKE $1
+func BuildSQLWhereReusingArgs(fields models.Fields) (string, []interface{}) {
+	p := &params{reuse: true}
+	query, err := buildSQLWhere(fields, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	return query, p.args
 }
 
-// ColumnsAliased return the column names with aliased of the table
-func ColumnsAliased(fields []string, aliased string) string {
-	if len(fields) == 0 {
-		return ""
-	}
-	columns := bytes.Buffer{}
-	for _, v := range fields {
-		columns.WriteString(fmt.Sprintf("%s.%s, ", aliased, v))
+// BuildSQLHaving builds and returns a query HAVING of postgres and its arguments,
+// the names of the fields are the aggregate expressions, ej: HAVING count(*) > $1.
+// If the fields are empty it returns an empty string and nil arguments because the HAVING is optional
+func BuildSQLHaving(fields models.Fields) (string, []interface{}) {
+	p := &params{}
+	query, err := buildConditions("HAVING", fields, p)
+	if err != nil {
+		return err.Error(), nil
 	}
 
-	return fmt.Sprintf("%s.id, %s%s.created_at, %s.updated_at",
-		aliased, columns.String(), aliased, aliased)
+	return query, p.args
 }
 
-// ColumnsAliasedWithDefault return the column names with aliased of the table
-func ColumnsAliasedWithDefault(fields []string, aliased string) string {
-	if len(fields) == 0 {
-		return ""
+// buildSQLWhere builds the query WHERE binding the arguments in p,
+// so the placeholders continue after the arguments that p already has
+func buildSQLWhere(fields models.Fields, p *params) (string, error) {
+	return buildConditions("WHERE", fields, p)
+}
+
+// buildTableWhere builds the query WHERE of the fields of the table adding the partial index filters
+// of the table, see SetPartialIndexFilters. The rendered conditions of the fields are wrapped,
+// so the groups of the fields are not changed: WHERE (name = $1 OR is_admin = $2) AND deleted_at IS NULL
+func buildTableWhere(table string, fields models.Fields, p *params) (string, error) {
+	predicates, ok := partialIndexFilters[table]
+	if !ok {
+		return buildSQLWhere(fields, p)
+	}
+	if fields.IsEmpty() {
+		return buildSQLWhere(predicates, p)
 	}
 
-	columns := bytes.Buffer{}
-	for _, v := range fields {
-		columns.WriteString(fmt.Sprintf("%s.%s, ", aliased, v))
+	query, err := buildSQLWhere(fields, p)
+	if err != nil {
+		return "", err
+	}
+
+	conditions, err := buildConditions("AND", predicates, p)
+	if err != nil {
+		return "", err
 	}
 
-	return fmt.Sprintf("%s.id, %s%s.created_at, %s.updated_at",
-		aliased, columns.String(), aliased, aliased)
+	return fmt.Sprintf("WHERE (%s) %s", strings.TrimPrefix(query, "WHERE "), conditions), nil
 }
 
-// CheckError validate a postgres error
-func CheckError(err error) error {
-	if psqlErr, ok := err.(*pq.Error); ok {
-		switch psqlErr.Code {
-		case "23505":
-			return models.ErrUnique
-		case "23503":
-			return models.ErrForeignKey
-		case "23502":
-			return models.ErrNotNull
-		}
+// buildConditions builds the conditions of the fields after the keyword (WHERE, HAVING)
+// binding the arguments in p, so the placeholders continue after the arguments that p already has
+func buildConditions(keyword string, fields models.Fields, p *params) (string, error) {
+	if fields.IsEmpty() {
+		return "", nil
 	}
-	return nil
-}
 
-func BuildIN(field models.Field) string {
-	nameField := strings.ToLower(field.Name)
-	// if the IN failed, return mistakeIN for not select nothing in the field
-	mistakeIN := fmt.Sprintf("%s = 0", nameField)
+	firstArg := len(p.args) + 1
 
-	args := bytes.Buffer{}
-	switch items := field.Value.(type) {
-	case []uint:
-		if len(items) == 0 {
-			return mistakeIN
-		}
+	query := bytes.Buffer{}
+	query.WriteString(keyword)
+	query.WriteString(" ")
+	length := len(fields)
+	lastFieldIndex := length - 1
+	nGroups := 0
 
-		for _, item := range items {
-			args.WriteString(fmt.Sprintf("%d,", item))
-		}
+	for key, field := range fields {
+		setDefaultValuesField(&field)
 
-		return fmt.Sprintf("%s IN (%s)", nameField, strings.TrimSuffix(args.String(), ","))
-	case []int:
-		if len(items) == 0 {
-			return mistakeIN
+		if opens := field.GroupOpens(); opens > 0 {
+			nGroups += opens
+			query.WriteString(strings.Repeat("(", opens))
 		}
 
-		for _, item := range items {
-			args.WriteString(fmt.Sprintf("%d,", item))
+		condition, err := buildCondition(field, p)
+		if err != nil {
+			return "", err
 		}
+		query.WriteString(condition)
 
-		return fmt.Sprintf("%s IN (%s)", nameField, strings.TrimSuffix(args.String(), ","))
-	case []string:
-		if len(items) == 0 {
-			return mistakeIN
-		}
+		// Close the group
+		if closes := field.GroupCloses(); closes > 0 {
+			if nGroups < closes {
+				return "", fmt.Errorf("%w: the field %s", models.ErrGroupCloseWithoutOpen, field.Name)
+			}
 
-		for _, item := range items {
-			args.WriteString(fmt.Sprintf("'%s',", item))
+			nGroups -= closes
+			query.WriteString(strings.Repeat(")", closes))
 		}
 
-		return fmt.Sprintf("%s IN (%s)", nameField, strings.TrimSuffix(args.String(), ","))
-	default:
+		// if exists still groups open, close them in the last field
+		if (nGroups > 0) && (key == lastFieldIndex) {
+			query.WriteString(strings.Repeat(")", nGroups))
+		}
+
+		// Add chainingKey (OR, AND) except in the last field
+		if key != lastFieldIndex {
+			query.WriteString(fmt.Sprintf(" %s ", field.ChainingKey))
+		}
+	}
+
+	// the placeholders are checked only for the numbered placeholders
+	if isNumberedDialect() {
+		if err := checkPlaceholders(query.String(), p.args, firstArg); err != nil {
+			return "", err
+		}
+	}
+
+	return query.String(), nil
+}
+
+// buildCondition builds the condition of the field binding its arguments in p
+func buildCondition(field models.Field, p *params) (string, error) {
+	if field.Raw != "" {
+		return buildRaw(field, p)
+	}
+
+	query := bytes.Buffer{}
+
+	switch field.Operator {
+	case models.In, models.NotIn:
+		if field.Operator == models.NotIn && field.NullSafe {
+			query.WriteString(buildNullSafeNotIN(field, p))
+			break
+		}
+
+		query.WriteString(buildINCondition(field, p))
+	case models.IsNull, models.IsNotNull:
+		query.WriteString(fmt.Sprintf("%s %s", strings.ToLower(field.Name), field.Operator))
+	case models.Any, models.All:
+		if err := field.ValidateArrayComparison(); err != nil {
+			return "", err
+		}
+
+		comparison := field.ArrayComparison
+		if comparison == "" {
+			comparison = models.Equals
+		}
+
+		// the array is bound as one param
+		query.WriteString(fmt.Sprintf("%s %s %s(%s)",
+			strings.ToLower(field.Name),
+			comparison,
+			field.Operator,
+			p.bind(pq.Array(field.Value)),
+		))
+	case models.JSONContains, models.JSONContainedBy:
+		value, err := jsonValue(field.Value)
+		if err != nil {
+			return "", err
+		}
+
+		query.WriteString(fmt.Sprintf("%s %s %s", strings.ToLower(field.Name), field.Operator, p.bind(value)))
+	case models.JSONHasKey:
+		// only the operator `?` can use the GIN indexes of the column, but it is the placeholder
+		// of the positional dialects, so they write its function: jsonb_exists(data, ?)
+		if !isNumberedDialect() {
+			query.WriteString(fmt.Sprintf("jsonb_exists(%s, %s)", strings.ToLower(field.Name), p.bind(field.Value)))
+			break
+		}
+
+		query.WriteString(fmt.Sprintf("%s %s %s", strings.ToLower(field.Name), field.Operator, p.bind(field.Value)))
+	case models.Exists, models.NotExists:
+		if field.RawSubquery == "" {
+			return "", fmt.Errorf("%w: the operator %s", models.ErrEmptySubquery, field.Operator)
+		}
+
+		// `EXISTS` has no column, the Name is ignored
+		query.WriteString(fmt.Sprintf("%s (%s)", field.Operator, field.RawSubquery))
+	case models.Between, models.BetweenSymmetric, models.NotBetween:
+		if err := field.ValidateFromAndToValues(); err != nil {
+			return "", err
+		}
+
+		// if the range is between the columns of other table
+		if field.IsValueFromTable {
+			if err := field.ValidateFromAndToColumns(); err != nil {
+				return "", err
+			}
+
+			query.WriteString(fmt.Sprintf("%s %s %s AND %s",
+				strings.ToLower(field.Name),
+				field.Operator,
+				strings.ToLower(field.FromValue.(string)),
+				strings.ToLower(field.ToValue.(string)),
+			))
+
+			break
+		}
+
+		if err := field.ValidateAtTimeZone(); err != nil {
+			return "", err
+		}
+
+		// `BETWEEN` has 2 params always
+		query.WriteString(fmt.Sprintf("%s %s %s AND %s",
+			atTimeZone(strings.ToLower(field.Name), field.AtTimeZone),
+			field.Operator,
+			p.bind(field.FromValue),
+			p.bind(field.ToValue),
+		))
+	case models.Overlaps:
+		if err := field.ValidateFromAndToValues(); err != nil {
+			return "", err
+		}
+		if err := field.ValidateRangeType(); err != nil {
+			return "", err
+		}
+		if err := field.ValidateRangeBounds(); err != nil {
+			return "", err
+		}
+
+		column := strings.ToLower(field.Name)
+		if field.RangeEndColumn != "" {
+			column = fmt.Sprintf("%s(%s, %s)", field.RangeType, column, strings.ToLower(field.RangeEndColumn))
+		}
+
+		query.WriteString(fmt.Sprintf("%s %s %s(%s, %s)",
+			column,
+			field.Operator,
+			field.RangeType,
+			p.bind(field.FromValue),
+			p.bind(field.ToValue),
+		))
+	default:
+		// if we need to compare against the column of other table
+		if field.IsValueFromTable {
+			query.WriteString(fmt.Sprintf("%s %s %s",
+				strings.ToLower(field.Name),
+				field.Operator,
+				strings.ToLower(field.NameValueFromTable),
+			))
+
+			break
+		}
+
+		// if we compare against the value of a correlated subquery
+		if field.Subquery != nil {
+			subquery, err := buildScalarSubquery(*field.Subquery)
+			if err != nil {
+				return "", err
+			}
+
+			query.WriteString(fmt.Sprintf("%s %s %s",
+				strings.ToLower(field.Name),
+				field.Operator,
+				subquery,
+			))
+
+			break
+		}
+
+		// if we compare against a value that we define
+		if err := field.ValidateArrayIndex(); err != nil {
+			return "", err
+		}
+		if err := field.ValidateAtTimeZone(); err != nil {
+			return "", err
+		}
+		if field.EnumType != "" && !isValidIdentifier(field.EnumType) {
+			return "", fmt.Errorf("%w: %s", models.ErrInvalidEnumType, field.EnumType)
+		}
+
+		nameField := strings.ToLower(field.Name)
+		if field.ArrayIndex != nil {
+			nameField = fmt.Sprintf("%s[%d]", nameField, *field.ArrayIndex)
+		}
+		if field.JSONKey != "" {
+			nameField = fmt.Sprintf("%s->>'%s'", nameField, strings.ReplaceAll(field.JSONKey, "'", "''"))
+		}
+		nameField = atTimeZone(nameField, field.AtTimeZone)
+		placeholder := p.bind(field.Value)
+		if field.EnumType != "" {
+			placeholder = fmt.Sprintf("%s::%s", placeholder, field.EnumType)
+		}
+		if field.Unaccent {
+			nameField = fmt.Sprintf("unaccent(%s)", nameField)
+			placeholder = fmt.Sprintf("unaccent(%s)", placeholder)
+		}
+		if field.Escape && field.IsPattern() {
+			placeholder = fmt.Sprintf(`%s ESCAPE '\'`, placeholder)
+		}
+
+		query.WriteString(fmt.Sprintf("%s %s %s",
+			nameField,
+			field.Operator,
+			placeholder,
+		))
+	}
+
+	return query.String(), nil
+}
+
+// BuildSQLCount builds and returns a query that counts the rows of the table filtered by the fields,
+// and its arguments, if the fields are empty it counts all the rows with nil arguments
+func BuildSQLCount(table string, fields models.Fields) (string, []interface{}) {
+	p := &params{}
+	conditions, err := buildTableWhere(table, fields, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s", table)
+	if conditions == "" {
+		return query, nil
+	}
+
+	return fmt.Sprintf("%s %s", query, conditions), p.args
+}
+
+// buildScalarSubquery builds the correlated subquery that returns one value,
+// ej: (SELECT avg(amount) FROM orders o2 WHERE o2.user_id = o.user_id)
+func buildScalarSubquery(subquery models.ScalarSubquery) (string, error) {
+	if subquery.Expression == "" {
+		return "", errors.New(ErrFieldsAreEmpty)
+	}
+
+	for _, identifier := range []string{subquery.Table, subquery.Alias, subquery.Column, subquery.OuterColumn} {
+		if !isValidIdentifier(identifier) {
+			return "", errors.New(ErrInvalidIdentifier)
+		}
+	}
+
+	return fmt.Sprintf("(SELECT %s FROM %s %s WHERE %s.%s = %s)",
+		subquery.Expression,
+		subquery.Table,
+		subquery.Alias,
+		subquery.Alias,
+		strings.ToLower(subquery.Column),
+		strings.ToLower(subquery.OuterColumn),
+	), nil
+}
+
+// atTimeZone returns the column converted to the time zone, if the time zone is empty it returns the column
+func atTimeZone(column, timeZone string) string {
+	if timeZone == "" {
+		return column
+	}
+
+	return fmt.Sprintf("%s AT TIME ZONE '%s'", column, timeZone)
+}
+
+// BuildSQLFacetCount builds and returns a query that counts the rows by each value of the facetColumn
+// filtered by the baseFilters, and its arguments
+func BuildSQLFacetCount(table, facetColumn string, baseFilters models.Fields) (string, []interface{}) {
+	facetColumn = strings.ToLower(facetColumn)
+	p := &params{}
+	conditions, err := buildTableWhere(table, baseFilters, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	query := fmt.Sprintf("SELECT %s, COUNT(*) FROM %s", facetColumn, table)
+	if conditions != "" {
+		query += " " + conditions
+	}
+
+	return fmt.Sprintf("%s GROUP BY %s", query, facetColumn), p.args
+}
+
+// BuildSQLExists builds and returns a query that checks if a row of the table matches the fields,
+// and its arguments, ej: SELECT EXISTS(SELECT 1 FROM users WHERE email = $1).
+// It is cheaper than a count because it stops at the first row
+func BuildSQLExists(table string, fields models.Fields) (string, []interface{}) {
+	p := &params{}
+	conditions, err := buildTableWhere(table, fields, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	subquery := fmt.Sprintf("SELECT 1 FROM %s", table)
+	if conditions != "" {
+		subquery += " " + conditions
+	}
+
+	return fmt.Sprintf("SELECT EXISTS(%s)", subquery), p.args
+}
+
+// BuildSQLCountByStatus builds and returns a query that counts the rows of each status in one row,
+// filtered by the baseFilters, and its arguments, the column of each count is named as its status, ej:
+// SELECT count(*) FILTER (WHERE status = $1) AS open, count(*) FILTER (WHERE status = $2) AS closed FROM tickets
+func BuildSQLCountByStatus(table, statusColumn string, statuses []string, baseFilters models.Fields) (string, []interface{}) {
+	if len(statuses) == 0 {
+		return ErrFieldsAreEmpty, nil
+	}
+
+	statusColumn = strings.ToLower(statusColumn)
+	p := &params{}
+	columns := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		if !isValidIdentifier(status) {
+			return ErrInvalidIdentifier, nil
+		}
+
+		columns = append(columns, fmt.Sprintf("count(*) FILTER (WHERE %s = %s) AS %s",
+			statusColumn, p.bind(status), strings.ToLower(status)))
+	}
+
+	conditions, err := buildTableWhere(table, baseFilters, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(columns, ", "), table)
+	if conditions != "" {
+		query += " " + conditions
+	}
+
+	return query, p.args
+}
+
+// BuildSQLJoins builds and returns the JOIN clauses of postgres in the given order
+func BuildSQLJoins(joins models.Joins) string {
+	if joins.IsEmpty() {
+		return ""
+	}
+
+	query := bytes.Buffer{}
+	for _, join := range joins {
+		setJoinType(&join)
+
+		query.WriteString(string(join.Type))
+		query.WriteString(" ")
+
+		if join.Lateral {
+			query.WriteString(fmt.Sprintf("LATERAL (%s)", join.Table))
+		} else {
+			query.WriteString(join.Table)
+		}
+
+		if join.Alias != "" {
+			query.WriteString(" ")
+			query.WriteString(join.Alias)
+		}
+
+		if join.Lateral && join.OnLeft == "" && join.OnRight == "" {
+			query.WriteString(" ON true ")
+			continue
+		}
+
+		query.WriteString(fmt.Sprintf(" ON %s %s %s ", join.OnLeft, join.Operator, join.OnRight))
+	}
+	query.Truncate(query.Len() - 1)
+
+	return query.String()
+}
+
+// BuildSQLOrderBy builds and returns a query ORDER BY of postgres and its arguments
+func BuildSQLOrderBy(sorts models.SortFields) string {
+	if sorts.IsEmpty() {
+		return ""
+	}
+
+	query := bytes.Buffer{}
+	query.WriteString("ORDER BY ")
+
+	for _, sort := range sorts {
+		setSortFieldOrder(&sort)
+		setSortFieldAliases(&sort)
+
+		name := strings.ToLower(sort.Name)
+		if sort.RawExpression != "" {
+			name = sort.RawExpression
+		}
+
+		query.WriteString(fmt.Sprintf("%s %s", name, sort.Order))
+		if sort.Nulls != "" {
+			query.WriteString(fmt.Sprintf(" %s", sort.Nulls))
+		}
+		query.WriteString(", ")
+	}
+	query.Truncate(query.Len() - 2)
+
+	return query.String()
+}
+
+// BuildSQLWindowColumn builds and returns a column computed with a window function of postgres
+func BuildSQLWindowColumn(column models.WindowColumn) (string, error) {
+	window := bytes.Buffer{}
+
+	if len(column.PartitionBy) > 0 {
+		window.WriteString("PARTITION BY ")
+		window.WriteString(strings.ToLower(strings.Join(column.PartitionBy, ", ")))
+		window.WriteString(" ")
+	}
+
+	if !column.OrderBy.IsEmpty() {
+		window.WriteString(BuildSQLOrderBy(column.OrderBy))
+		window.WriteString(" ")
+	}
+
+	if !column.Frame.IsEmpty() {
+		if err := column.Frame.Validate(); err != nil {
+			return "", err
+		}
+
+		window.WriteString(buildSQLWindowFrame(column.Frame))
+		window.WriteString(" ")
+	}
+
+	return fmt.Sprintf("%s OVER (%s) AS %s",
+		column.Function,
+		strings.TrimSuffix(window.String(), " "),
+		column.Alias,
+	), nil
+}
+
+func buildSQLWindowFrame(frame models.WindowFrame) string {
+	if frame.End == "" {
+		return fmt.Sprintf("%s %s", frame.Mode, frame.Start)
+	}
+
+	return fmt.Sprintf("%s BETWEEN %s AND %s", frame.Mode, frame.Start, frame.End)
+}
+
+// BuildSQLGroupBy builds and returns a query GROUP BY of postgres, the columns can be
+// qualified by the source, ej: GROUP BY c.employer_id, status.
+// If the fields are empty it returns an empty string because the GROUP BY is optional
+func BuildSQLGroupBy(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !isValidIdentifier(field) {
+			return ErrInvalidIdentifier
+		}
+		columns = append(columns, strings.ToLower(field))
+	}
+
+	return "GROUP BY " + strings.Join(columns, ", ")
+}
+
+// BuildSQLGroupByMode builds and returns a query GROUP BY of postgres with ROLLUP, CUBE or GROUPING SETS.
+// Each set is an element of the grouping, a set with several columns is wrapped with parentheses, ej:
+// Rollup with [[a], [b, c]] returns GROUP BY ROLLUP (a, (b, c)).
+// Only GROUPING SETS allows an empty set, it is the grand total: GROUPING SETS ((a), ())
+func BuildSQLGroupByMode(mode models.GroupingMode, sets [][]string) string {
+	if len(sets) == 0 {
+		return ErrFieldsAreEmpty
+	}
+
+	switch mode {
+	case models.Rollup, models.Cube, models.GroupingSets:
+	default:
+		return ErrInvalidGroupingMode
+	}
+
+	elements := make([]string, 0, len(sets))
+	for _, set := range sets {
+		if len(set) == 0 && mode != models.GroupingSets {
+			return ErrFieldsAreEmpty
+		}
+
+		columns := make([]string, 0, len(set))
+		for _, column := range set {
+			if !isValidIdentifier(column) {
+				return ErrInvalidIdentifier
+			}
+			columns = append(columns, strings.ToLower(column))
+		}
+
+		if len(columns) == 1 && mode != models.GroupingSets {
+			elements = append(elements, columns[0])
+			continue
+		}
+
+		elements = append(elements, fmt.Sprintf("(%s)", strings.Join(columns, ", ")))
+	}
+
+	return fmt.Sprintf("GROUP BY %s (%s)", mode, strings.Join(elements, ", "))
+}
+
+// BuildSQLAggregateColumn builds and returns a column that aggregates the values of a group
+// with array_agg or string_agg, ej: string_agg(name, ', ' ORDER BY name ASC) AS names
+func BuildSQLAggregateColumn(column models.AggregateColumn) string {
+	name := strings.ToLower(column.Name)
+	if column.Source != "" {
+		name = fmt.Sprintf("%s.%s", column.Source, name)
+	}
+
+	args := bytes.Buffer{}
+	args.WriteString(name)
+
+	if column.Function == models.StringAgg {
+		args.WriteString(fmt.Sprintf(", '%s'", strings.ReplaceAll(column.Separator, "'", "''")))
+	}
+
+	if !column.OrderBy.IsEmpty() {
+		args.WriteString(" ")
+		args.WriteString(BuildSQLOrderBy(column.OrderBy))
+	}
+
+	return fmt.Sprintf("%s(%s) AS %s", column.Function, args.String(), column.Alias)
+}
+
+// BuildSQLLock builds and returns the locking clause of postgres for a select,
+// ej: FOR UPDATE OF contracts, periods.
+// If the lock is empty it returns an empty string because the lock is optional
+func BuildSQLLock(lock models.Lock) string {
+	if lock.IsEmpty() {
+		return ""
+	}
+
+	switch lock.Mode {
+	case models.ForUpdate, models.ForNoKeyUpdate, models.ForShare, models.ForKeyShare:
+	default:
+		return ErrInvalidLockMode
+	}
+
+	if len(lock.OfTables) == 0 {
+		return string(lock.Mode)
+	}
+
+	for _, table := range lock.OfTables {
+		if !isValidIdentifier(table) {
+			return ErrInvalidIdentifier
+		}
+	}
+
+	return fmt.Sprintf("%s OF %s", lock.Mode, strings.Join(lock.OfTables, ", "))
+}
+
+// BuildSQLPagination builds and returns a query OFFSET LIMIT of postgres for pagination,
+// with StrictPagination it returns ErrInvalidPagination if the pagination is not valid
+func BuildSQLPagination(pag models.Pagination) string {
+	if pag.IsZero() {
+		return ""
+	}
+
+	if StrictPagination {
+		if err := validatePagination(pag); err != nil {
+			return ErrInvalidPagination
+		}
+	}
+
+	limit, offset := limitAndOffset(pag)
+
+	pagination := fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+
+	return pagination
+}
+
+// BuildSQLFetch builds and returns a query OFFSET FETCH of the SQL standard for pagination,
+// with StrictPagination it returns ErrInvalidPagination if the pagination is not valid
+func BuildSQLFetch(pag models.Pagination) string {
+	if pag.IsZero() {
+		return ""
+	}
+
+	if StrictPagination {
+		if err := validatePagination(pag); err != nil {
+			return ErrInvalidPagination
+		}
+	}
+
+	limit, offset := limitAndOffset(pag)
+
+	return fmt.Sprintf("OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, limit)
+}
+
+// BuildQueryArgsAndPagination builds and returns a query adding the filter + sort + pagination
+func BuildQueryArgsAndPagination(initialSQL string, filter models.Fields, sort models.SortFields, pag models.Pagination) (string, []interface{}) {
+	conditions, args := BuildSQLWhere(filter)
+	query := initialSQL + " " + conditions
+
+	query += " " + BuildSQLOrderBy(sort)
+	query += " " + BuildSQLPagination(pag)
+
+	return query, args
+}
+
+// StripOrderByAndPagination removes the trailing ORDER BY and LIMIT/OFFSET/FETCH clauses
+// of a query, so it can be wrapped in a count: SELECT COUNT(*) FROM (query) sub.
+// The clauses inside subqueries (parentheses) or quoted texts are not removed
+func StripOrderByAndPagination(query string) string {
+	upperQuery := strings.ToUpper(query)
+	depth := 0
+	inQuote := false
+
+	for i := 0; i < len(upperQuery); i++ {
+		switch c := upperQuery[i]; {
+		case c == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && isTrailingClauseAt(upperQuery, i):
+			return strings.TrimSpace(query[:i])
+		}
+	}
+
+	return strings.TrimSpace(query)
+}
+
+// isTrailingClauseAt returns if an ORDER BY, LIMIT, OFFSET or FETCH keyword begins at the position i
+func isTrailingClauseAt(upperQuery string, i int) bool {
+	if i > 0 && !isClauseBoundary(upperQuery[i-1]) {
+		return false
+	}
+
+	for _, keyword := range []string{"ORDER BY", "LIMIT", "OFFSET", "FETCH"} {
+		end := i + len(keyword)
+		if !strings.HasPrefix(upperQuery[i:], keyword) {
+			continue
+		}
+		if end == len(upperQuery) || isClauseBoundary(upperQuery[end]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isClauseBoundary(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\t' || c == '\r' || c == '(' || c == ')'
+}
+
+// BuildSQLValuesCTE builds and returns a CTE of postgres with a parameterized VALUES list and its arguments,
+// ej: WITH data(id, val) AS (VALUES ($1, $2), ($3, $4))
+func BuildSQLValuesCTE(name string, columns []string, rows [][]interface{}) (string, []interface{}) {
+	if len(columns) == 0 {
+		return ErrFieldsAreEmpty, nil
+	}
+	if len(rows) == 0 {
+		return ErrRowsAreEmpty, nil
+	}
+
+	p := &params{}
+	values := bytes.Buffer{}
+	for _, row := range rows {
+		if len(row) != len(columns) {
+			return ErrRowsColumnsMissMatch, nil
+		}
+
+		values.WriteString("(")
+		for _, value := range row {
+			values.WriteString(p.bind(value))
+			values.WriteString(", ")
+		}
+		values.Truncate(values.Len() - 2)
+		values.WriteString("), ")
+	}
+	values.Truncate(values.Len() - 2)
+
+	return fmt.Sprintf("WITH %s(%s) AS (VALUES %s)", name, strings.Join(columns, ", "), values.String()), p.args
+}
+
+// BuildSQLInsertCTE builds and returns a query that inserts the values in a CTE of postgres
+// and selects the inserted rows filtered by the fields, and its arguments, the placeholders
+// of the filters continue after the values, ej: WITH ins AS (INSERT INTO logs (a, b)
+// VALUES ($1, $2) RETURNING *) SELECT id, a FROM ins WHERE a = $3
+func BuildSQLInsertCTE(name, table string, fields []string, values []interface{}, selectFields []string, filters models.Fields) (string, []interface{}) {
+	if len(fields) == 0 || len(selectFields) == 0 {
+		return ErrFieldsAreEmpty, nil
+	}
+	if len(values) != len(fields) {
+		return ErrRowsColumnsMissMatch, nil
+	}
+	if !isValidIdentifier(name) {
+		return ErrInvalidIdentifier, nil
+	}
+
+	p := &params{args: append([]interface{}{}, values...)}
+	conditions, err := buildSQLWhere(filters, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	query := fmt.Sprintf("WITH %s AS (%s RETURNING *) %s",
+		name,
+		buildSQLInsertValues(table, fields),
+		BuildSQLSelectFields(name, selectFields),
+	)
+	if conditions != "" {
+		query += " " + conditions
+	}
+
+	return query, p.args
+}
+
+// BuildSQLRowToJSON builds a query that returns each row of the subquery as a JSON object
+func BuildSQLRowToJSON(subquery string) string {
+	return fmt.Sprintf("SELECT row_to_json(t) FROM (%s) t", subquery)
+}
+
+// BuildSQLJSONAgg builds a query that returns all the rows of the subquery as one JSON array,
+// the array is NULL when the subquery has no rows
+func BuildSQLJSONAgg(subquery string) string {
+	return fmt.Sprintf("SELECT json_agg(t) FROM (%s) t", subquery)
+}
+
+// BuildSQLDelete builds and returns a query with the DELETE statement
+func BuildSQLDelete(table string) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE %s = %s", table, config.IDColumn, placeholder(1))
+}
+
+// BuildSQLDeleteBatch builds and returns a query that deletes at most batchSize rows that match
+// the conditions, and its arguments, postgres has no DELETE LIMIT so the rows are limited in a subquery:
+// DELETE FROM t WHERE id IN (SELECT id FROM t WHERE status = $1 LIMIT 1000).
+// It is executed until it deletes no rows
+func BuildSQLDeleteBatch(table string, conditions models.Fields, batchSize uint) (string, []interface{}) {
+	if batchSize == 0 {
+		return ErrInvalidRowCount, nil
+	}
+
+	p := &params{}
+	where, err := buildTableWhere(table, conditions, p)
+	if err != nil {
+		return err.Error(), nil
+	}
+
+	subquery := fmt.Sprintf("SELECT %s FROM %s", config.IDColumn, table)
+	if where != "" {
+		subquery += " " + where
+	}
+
+	return fmt.Sprintf("DELETE FROM %s WHERE %s IN (%s LIMIT %d)", table, config.IDColumn, subquery, batchSize), p.args
+}
+
+// BuildSQLSoftDeleteByID builds and returns a query that soft deletes a row setting its deleted_at
+func BuildSQLSoftDeleteByID(table string) string {
+	return fmt.Sprintf("UPDATE %s SET deleted_at = now() WHERE %s = %s", table, config.IDColumn, placeholder(1))
+}
+
+// ColumnsAliased return the column names with aliased of the table
+func ColumnsAliased(fields []string, aliased string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	columns := bytes.Buffer{}
+	for _, v := range fields {
+		columns.WriteString(fmt.Sprintf("%s.%s, ", aliased, v))
+	}
+
+	return fmt.Sprintf("%s.%s, %s%s.%s, %s.%s",
+		aliased, config.IDColumn, columns.String(), aliased, config.CreatedAtColumn, aliased, config.UpdatedAtColumn)
+}
+
+// ColumnsAliasedWithDefault return the column names with aliased of the table
+func ColumnsAliasedWithDefault(fields []string, aliased string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	columns := bytes.Buffer{}
+	for _, v := range fields {
+		columns.WriteString(fmt.Sprintf("%s.%s, ", aliased, v))
+	}
+
+	return fmt.Sprintf("%s.%s, %s%s.%s, %s.%s",
+		aliased, config.IDColumn, columns.String(), aliased, config.CreatedAtColumn, aliased, config.UpdatedAtColumn)
+}
+
+// CheckError validate a postgres error
+func CheckError(err error) error {
+	if psqlErr, ok := err.(*pq.Error); ok {
+		switch psqlErr.Code {
+		case "23505":
+			return models.ErrUnique
+		case "23503":
+			return models.ErrForeignKey
+		case "23502":
+			return models.ErrNotNull
+		}
+	}
+	return nil
+}
+
+// BuildIN builds the IN of the field with its values inline, if the operator of the field
+// is NotIn it builds a NOT IN. Beware that a NOT IN never matches the rows where
+// the column is NULL, and if a value is NULL it matches nothing, see models.Field.NullSafe
+func BuildIN(field models.Field) string {
+	nameField := strings.ToLower(field.Name)
+	operator := models.In
+	// if the IN failed, return mistakeIN for not select nothing in the field
+	mistakeIN := fmt.Sprintf("%s = 0", nameField)
+	emptyIN := mistakeIN
+	if field.Operator == models.NotIn {
+		operator = models.NotIn
+		// excluding nothing selects everything, but a failed NOT IN selects nothing
+		emptyIN = "TRUE"
+		mistakeIN = "FALSE"
+	}
+
+	args := bytes.Buffer{}
+	switch items := field.Value.(type) {
+	case []uint:
+		if len(items) == 0 {
+			return emptyIN
+		}
+
+		for _, item := range items {
+			args.WriteString(fmt.Sprintf("%d,", item))
+		}
+
+		return fmt.Sprintf("%s %s (%s)", nameField, operator, strings.TrimSuffix(args.String(), ","))
+	case []int:
+		if len(items) == 0 {
+			return emptyIN
+		}
+
+		for _, item := range items {
+			args.WriteString(fmt.Sprintf("%d,", item))
+		}
+
+		return fmt.Sprintf("%s %s (%s)", nameField, operator, strings.TrimSuffix(args.String(), ","))
+	case []int64:
+		if len(items) == 0 {
+			return emptyIN
+		}
+
+		for _, item := range items {
+			args.WriteString(fmt.Sprintf("%d,", item))
+		}
+
+		return fmt.Sprintf("%s %s (%s)", nameField, operator, strings.TrimSuffix(args.String(), ","))
+	case []float64:
+		if len(items) == 0 {
+			return emptyIN
+		}
+
+		for _, item := range items {
+			args.WriteString(strconv.FormatFloat(item, 'f', -1, 64))
+			args.WriteString(",")
+		}
+
+		return fmt.Sprintf("%s %s (%s)", nameField, operator, strings.TrimSuffix(args.String(), ","))
+	case []bool:
+		if len(items) == 0 {
+			return emptyIN
+		}
+
+		for _, item := range items {
+			args.WriteString(strings.ToUpper(strconv.FormatBool(item)))
+			args.WriteString(",")
+		}
+
+		return fmt.Sprintf("%s %s (%s)", nameField, operator, strings.TrimSuffix(args.String(), ","))
+	case []string:
+		if len(items) == 0 {
+			return emptyIN
+		}
+
+		if field.IgnoreCase {
+			nameField = fmt.Sprintf("lower(%s)", nameField)
+		}
+
+		for _, item := range items {
+			if field.IgnoreCase {
+				item = strings.ToLower(item)
+			}
+			// the quotes are escaped doubling them, the backslash is literal in a standard string
+			args.WriteString(fmt.Sprintf("'%s',", strings.ReplaceAll(item, "'", "''")))
+		}
+
+		return fmt.Sprintf("%s %s (%s)", nameField, operator, strings.TrimSuffix(args.String(), ","))
+	default:
 		return mistakeIN
 	}
 }
 
+// buildRaw returns the raw condition of the field replacing its `?` markers by the placeholders
+// of its args in order, the `?` into quotes aren't markers and `??` is a literal `?`.
+// The same rules apply without args, so the raw condition is written the same way
+func buildRaw(field models.Field, p *params) (string, error) {
+	// the parts are the text between the markers
+	parts := []string{}
+	part := bytes.Buffer{}
+	inQuotes := false
+	for i := 0; i < len(field.Raw); i++ {
+		c := field.Raw[i]
+		switch {
+		case c == '\'':
+			inQuotes = !inQuotes
+		case c == '?' && !inQuotes && i+1 < len(field.Raw) && field.Raw[i+1] == '?':
+			// `??` is a literal `?`, ej: the operator of jsonb
+			i++
+		case c == '?' && !inQuotes:
+			parts = append(parts, part.String())
+			part.Reset()
+			continue
+		}
+		part.WriteByte(c)
+	}
+	parts = append(parts, part.String())
+
+	if markers := len(parts) - 1; markers != len(field.Args) {
+		return "", fmt.Errorf("%w: the raw condition has %d markers and %d args", models.ErrPlaceholdersAndArgsMissMatch, markers, len(field.Args))
+	}
+
+	query := bytes.Buffer{}
+	for k, arg := range field.Args {
+		query.WriteString(parts[k])
+		query.WriteString(p.bind(arg))
+	}
+	query.WriteString(parts[len(parts)-1])
+
+	return query.String(), nil
+}
+
+// jsonValue returns the value as the text of a json, the strings and the bytes
+// are already a json so they are returned as is
+func jsonValue(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case string, []byte:
+		return value, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// buildINCondition builds the IN or NOT IN field with its subquery or its values,
+// the values are bound as an array, as parameters or written inline, see BuildSQLWhere
+func buildINCondition(field models.Field, p *params) string {
+	if field.Value == nil && field.RawSubquery != "" {
+		return fmt.Sprintf("%s %s (%s)", strings.ToLower(field.Name), field.Operator, field.RawSubquery)
+	}
+
+	if isINAboveThreshold(field) {
+		return buildANY(field, p)
+	}
+
+	if isINParameterized(field) {
+		return buildINParams(field, p)
+	}
+
+	return BuildIN(field)
+}
+
+// buildNullSafeNotIN builds the NOT IN field as NOT EXISTS (SELECT 1 WHERE name IN (...)),
+// the IN is NULL when the column or a value is NULL, and the WHERE of the subquery
+// takes it as false, so only a value equal to the column excludes the row
+func buildNullSafeNotIN(field models.Field, p *params) string {
+	if field.Value != nil || field.RawSubquery == "" {
+		values := reflect.ValueOf(field.Value)
+		if values.Kind() == reflect.Slice && values.Len() == 0 {
+			// excluding nothing selects everything
+			return "TRUE"
+		}
+	}
+
+	field.Operator = models.In
+	in := buildINCondition(field, p)
+
+	// the IN failed, so the NOT IN selects nothing
+	if in == fmt.Sprintf("%s = 0", strings.ToLower(field.Name)) {
+		return "FALSE"
+	}
+
+	return fmt.Sprintf("NOT EXISTS (SELECT 1 WHERE %s)", in)
+}
+
+// buildANY builds the IN field as `name = ANY($1)` binding its values as a postgres array,
+// and the NOT IN field as `name <> ALL($1)`
+func buildANY(field models.Field, p *params) string {
+	nameField := strings.ToLower(field.Name)
+	value := field.Value
+
+	if items, ok := value.([]string); ok && field.IgnoreCase {
+		nameField = fmt.Sprintf("lower(%s)", nameField)
+		lowerItems := make([]string, 0, len(items))
+		for _, item := range items {
+			lowerItems = append(lowerItems, strings.ToLower(item))
+		}
+		value = lowerItems
+	}
+
+	if field.Operator == models.NotIn {
+		return fmt.Sprintf("%s <> ALL(%s)", nameField, p.bind(pq.Array(value)))
+	}
+
+	return fmt.Sprintf("%s = ANY(%s)", nameField, p.bind(pq.Array(value)))
+}
+
+// buildINParams builds the IN or NOT IN field binding each value as a parameter
+func buildINParams(field models.Field, p *params) string {
+	nameField := strings.ToLower(field.Name)
+	if _, ok := field.Value.([]string); ok && field.IgnoreCase {
+		nameField = fmt.Sprintf("lower(%s)", nameField)
+	}
+
+	values := reflect.ValueOf(field.Value)
+	placeholders := make([]string, 0, values.Len())
+	for k := 0; k < values.Len(); k++ {
+		item := values.Index(k).Interface()
+		if text, ok := item.(string); ok && field.IgnoreCase {
+			item = strings.ToLower(text)
+		}
+		placeholders = append(placeholders, p.bind(item))
+	}
+
+	return fmt.Sprintf("%s %s (%s)", nameField, field.Operator, strings.Join(placeholders, ", "))
+}
+
+// isINParameterized returns if the values of the IN or NOT IN field must be bound as parameters,
+// the strings are bound unless InlineStringIN, and any value if ParameterizeIN
+func isINParameterized(field models.Field) bool {
+	values := reflect.ValueOf(field.Value)
+	if values.Kind() != reflect.Slice || values.Len() == 0 {
+		return false
+	}
+
+	if ParameterizeIN {
+		return true
+	}
+
+	_, isString := field.Value.([]string)
+	return isString && !InlineStringIN
+}
+
+// isINAboveThreshold returns if the values of the IN or NOT IN field are more than InAnyThreshold
+func isINAboveThreshold(field models.Field) bool {
+	value := reflect.ValueOf(field.Value)
+	if value.Kind() != reflect.Slice {
+		return false
+	}
+
+	return value.Len() > InAnyThreshold
+}
+
+// checkPlaceholders validates that the numbered placeholders of the DefaultDialect in the query
+// are $firstArg..$N where N is the number of arguments, the quoted texts are ignored.
+// The placeholders before firstArg are allowed because they can be reused
+func checkPlaceholders(query string, args []interface{}, firstArg int) error {
+	used := make(map[int]bool)
+	for k, part := range strings.Split(query, "'") {
+		// the odd parts are into quotes
+		if k%2 == 1 {
+			continue
+		}
+
+		for _, match := range placeholderPattern().FindAllStringSubmatch(part, -1) {
+			n, _ := strconv.Atoi(match[1])
+			used[n] = true
+		}
+	}
+
+	placeholders := 0
+	for n := range used {
+		if n >= firstArg {
+			placeholders++
+		}
+	}
+
+	if nArgs := len(args) - firstArg + 1; placeholders != nArgs {
+		return fmt.Errorf("%w: %d placeholders and %d args", models.ErrPlaceholdersAndArgsMissMatch, placeholders, nArgs)
+	}
+	for n := firstArg; n <= len(args); n++ {
+		if !used[n] {
+			return fmt.Errorf("%w: missing placeholder $%d", models.ErrPlaceholdersAndArgsMissMatch, n)
+		}
+	}
+
+	return nil
+}
+
+// isValidIdentifier returns if the name is a valid identifier of postgres
+func isValidIdentifier(name string) bool {
+	return identifierRegexp.MatchString(name)
+}
+
 func setDefaultValuesField(field *models.Field) {
 	setChainingField(field)
 	setOperatorField(field)
 	setAliases(field)
-	setGroupOpen(field)
 }
 
 func setChainingField(field *models.Field) {
@@ -421,11 +1718,24 @@ func setAliases(field *models.Field) {
 	if field.SourceNameValueFromTable != "" {
 		field.NameValueFromTable = fmt.Sprintf("%s.%s", field.SourceNameValueFromTable, field.NameValueFromTable)
 	}
+
+	if field.IsBetween() && field.IsValueFromTable && field.SourceNameValueFromTable != "" {
+		if from, ok := field.FromValue.(string); ok {
+			field.FromValue = fmt.Sprintf("%s.%s", field.SourceNameValueFromTable, from)
+		}
+		if to, ok := field.ToValue.(string); ok {
+			field.ToValue = fmt.Sprintf("%s.%s", field.SourceNameValueFromTable, to)
+		}
+	}
 }
 
-func setGroupOpen(field *models.Field) {
-	if field.GroupOpen {
-		field.Name = fmt.Sprintf("(%s", field.Name)
+func setJoinType(join *models.Join) {
+	if join.Type == "" {
+		join.Type = models.InnerJoin
+	}
+
+	if join.Operator == "" {
+		join.Operator = models.Equals
 	}
 }
 
@@ -440,3 +1750,85 @@ func setSortFieldAliases(sortField *models.SortField) {
 		sortField.Name = fmt.Sprintf("%s.%s", sortField.Source, sortField.Name)
 	}
 }
+
+// validatePagination validates the pagination and its offset with the limit that is used
+// to paginate, so an empty limit is validated with the MaxLimit or the DefaultMaxLimit
+func validatePagination(pag models.Pagination) error {
+	if err := pag.Validate(); err != nil {
+		return err
+	}
+
+	limit, _ := limitAndOffset(pag)
+
+	return pag.ValidateOffset(limit)
+}
+
+// limitAndOffset returns the limit and the offset of the pagination applying the default values,
+// the Offset of the pagination is used as is when it is set
+func limitAndOffset(pag models.Pagination) (uint, uint) {
+	if pag.MaxLimit == 0 {
+		pag.MaxLimit = DefaultMaxLimit
+	}
+
+	if pag.Limit == 0 || pag.Limit > pag.MaxLimit {
+		pag.Limit = pag.MaxLimit
+	}
+
+	if pag.Offset > 0 {
+		return pag.Limit, pag.Offset
+	}
+
+	if pag.Page == 0 {
+		pag.Page = 1
+	}
+
+	return pag.Limit, pag.Page*pag.Limit - pag.Limit
+}
+
+// params contains the arguments of a query and builds its placeholders
+type params struct {
+	args []interface{}
+
+	// reuse allows to share the placeholder between identical values
+	reuse bool
+}
+
+// bind adds the value to the arguments and returns its placeholder
+func (p *params) bind(value interface{}) string {
+	if p.reuse && isNumberedDialect() {
+		for k, arg := range p.args {
+			if isSameValue(arg, value) {
+				return placeholder(k + 1)
+			}
+		}
+	}
+
+	p.args = append(p.args, value)
+
+	return placeholder(len(p.args))
+}
+
+// isSameValue returns if both values have the same type and are equals,
+// the values of not comparable types (slices, maps) are never the same.
+//
+// A comparable type can hold a not comparable value, like pq.GenericArray
+// which is a struct with an interface{} field holding a slice, comparing
+// them panics, so that panic is recovered and the values are not the same
+func isSameValue(a, b interface{}) (same bool) {
+	if a == nil || b == nil {
+		return false
+	}
+
+	typeA := reflect.TypeOf(a)
+	if typeA != reflect.TypeOf(b) || !typeA.Comparable() {
+		return false
+	}
+
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+
+	return a == b
+}
@@ -1,14 +1,135 @@
 package postgres
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/lib/pq"
+
 	"github.com/AJRDRGZ/db-query-builder/models"
 
 	"github.com/stretchr/testify/assert"
 )
 
+func TestParseConstraintName(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		wantTable  string
+		wantColumn string
+		wantKind   string
+	}{
+		{
+			name:       "foreign key",
+			constraint: "orders_user_id_fkey",
+			wantTable:  "orders",
+			wantColumn: "user_id",
+			wantKind:   "fkey",
+		},
+		{
+			name:       "foreign key of a column without underscores",
+			constraint: "orders_user_fkey",
+			wantTable:  "orders",
+			wantColumn: "user",
+			wantKind:   "fkey",
+		},
+		{
+			name:       "table with underscores is split by the first underscore",
+			constraint: "order_items_product_id_fkey",
+			wantTable:  "order",
+			wantColumn: "items_product_id",
+			wantKind:   "fkey",
+		},
+		{
+			name:       "unique key",
+			constraint: "users_email_key",
+			wantTable:  "users",
+			wantColumn: "email",
+			wantKind:   "key",
+		},
+		{
+			name:       "primary key",
+			constraint: "users_pkey",
+			wantTable:  "users",
+			wantColumn: "",
+			wantKind:   "pkey",
+		},
+		{
+			name:       "custom name",
+			constraint: "unique_user_email",
+		},
+		{
+			name:       "suffix without table and column",
+			constraint: "users_fkey",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTable, gotColumn, gotKind := ParseConstraintName(tt.constraint)
+			assert.Equal(t, tt.wantTable, gotTable)
+			assert.Equal(t, tt.wantColumn, gotColumn)
+			assert.Equal(t, tt.wantKind, gotKind)
+		})
+	}
+}
+
+func TestParseConstraintNameOfTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		table      string
+		wantColumn string
+		wantKind   string
+	}{
+		{
+			name:       "foreign key",
+			constraint: "order_items_product_id_fkey",
+			table:      "order_items",
+			wantColumn: "product_id",
+			wantKind:   "fkey",
+		},
+		{
+			name:       "foreign key of a table without underscores",
+			constraint: "orders_user_id_fkey",
+			table:      "orders",
+			wantColumn: "user_id",
+			wantKind:   "fkey",
+		},
+		{
+			name:       "unique key",
+			constraint: "orders_user_id_key",
+			table:      "orders",
+			wantColumn: "user_id",
+			wantKind:   "key",
+		},
+		{
+			name:       "primary key",
+			constraint: "order_items_pkey",
+			table:      "order_items",
+			wantKind:   "pkey",
+		},
+		{
+			name:       "other table",
+			constraint: "order_items_product_id_fkey",
+			table:      "orders",
+		},
+		{
+			name:       "custom name",
+			constraint: "order_items_unique_product",
+			table:      "order_items",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotColumn, gotKind := ParseConstraintNameOfTable(tt.constraint, tt.table)
+			assert.Equal(t, tt.wantColumn, gotColumn)
+			assert.Equal(t, tt.wantKind, gotKind)
+		})
+	}
+}
+
 func TestBuildSQLInsert(t *testing.T) {
 	tableTest := []struct {
 		table  string
@@ -37,6 +158,146 @@ func TestBuildSQLInsert(t *testing.T) {
 	}
 }
 
+func TestBuildSQLInsertReturning(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		fields    []string
+		returning []string
+		want      string
+	}{
+		{
+			name:      "custom returning",
+			fields:    []string{"title", "body"},
+			returning: []string{"id", "uuid", "slug"},
+			want:      "INSERT INTO posts (title, body) VALUES ($1, $2) RETURNING id, uuid, slug",
+		},
+		{
+			name:      "default returning",
+			fields:    []string{"title", "body"},
+			returning: nil,
+			want:      "INSERT INTO posts (title, body) VALUES ($1, $2) RETURNING id, created_at",
+		},
+		{
+			name:      "empty fields",
+			fields:    []string{},
+			returning: []string{"id"},
+			want:      ErrFieldsAreEmpty,
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLInsertReturning("posts", tt.fields, tt.returning), tt.name)
+	}
+}
+
+func TestBuildSQLBulkInsert(t *testing.T) {
+	tableTest := []struct {
+		table    string
+		fields   []string
+		rowCount int
+		want     string
+	}{
+		{
+			table:    "cashboxes",
+			fields:   []string{"responsable", "country"},
+			rowCount: 1,
+			want:     "INSERT INTO cashboxes (responsable, country) VALUES ($1, $2) RETURNING id, created_at",
+		},
+		{
+			table:    "cashboxes",
+			fields:   []string{"responsable", "country"},
+			rowCount: 2,
+			want:     "INSERT INTO cashboxes (responsable, country) VALUES ($1, $2), ($3, $4) RETURNING id, created_at",
+		},
+		{
+			table:    "cashboxes",
+			fields:   []string{"responsable", "country", "account"},
+			rowCount: 3,
+			want:     "INSERT INTO cashboxes (responsable, country, account) VALUES ($1, $2, $3), ($4, $5, $6), ($7, $8, $9) RETURNING id, created_at",
+		},
+		{
+			table:    "empty",
+			fields:   []string{},
+			rowCount: 2,
+			want:     ErrFieldsAreEmpty,
+		},
+		{
+			table:    "cashboxes",
+			fields:   []string{"responsable"},
+			rowCount: 0,
+			want:     ErrInvalidRowCount,
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLBulkInsert(tt.table, tt.fields, tt.rowCount))
+	}
+}
+
+func TestChunkBulkInsert(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		fields    []string
+		rowCount  int
+		maxParams int
+		want      [][2]int
+	}{
+		{
+			name:      "rows fit in one statement",
+			fields:    []string{"a", "b", "c"},
+			rowCount:  3,
+			maxParams: 9,
+			want:      [][2]int{{0, 3}},
+		},
+		{
+			name:      "exact boundary",
+			fields:    []string{"a", "b", "c"},
+			rowCount:  6,
+			maxParams: 9,
+			want:      [][2]int{{0, 3}, {3, 6}},
+		},
+		{
+			name:      "last chunk is partial",
+			fields:    []string{"a", "b", "c"},
+			rowCount:  7,
+			maxParams: 10,
+			want:      [][2]int{{0, 3}, {3, 6}, {6, 7}},
+		},
+		{
+			name:      "postgres limit by default",
+			fields:    []string{"a", "b"},
+			rowCount:  70000,
+			maxParams: 0,
+			want:      [][2]int{{0, 32767}, {32767, 65534}, {65534, 70000}},
+		},
+		{
+			name:      "a row exceeds the limit",
+			fields:    []string{"a", "b", "c"},
+			rowCount:  2,
+			maxParams: 2,
+			want:      nil,
+		},
+		{
+			name:      "empty fields",
+			fields:    []string{},
+			rowCount:  2,
+			maxParams: 10,
+			want:      nil,
+		},
+		{
+			name:      "no rows",
+			fields:    []string{"a"},
+			rowCount:  0,
+			maxParams: 10,
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, ChunkBulkInsert(tt.fields, tt.rowCount, tt.maxParams), tt.name)
+	}
+}
+
 func TestBuildSQLInsertWithID(t *testing.T) {
 	tableTest := []struct {
 		table  string
@@ -93,6 +354,82 @@ func TestBuildSQLUpdateByID(t *testing.T) {
 	}
 }
 
+func TestBuildSQLUpdateByColumn(t *testing.T) {
+	tableTest := []struct {
+		table    string
+		fields   []string
+		idColumn string
+		want     string
+	}{
+		{
+			table:    "products",
+			fields:   []string{"name", "price"},
+			idColumn: "uuid",
+			want:     "UPDATE products SET name = $1, price = $2, updated_at = now() WHERE uuid = $3",
+		},
+		{
+			table:    "countries",
+			fields:   []string{"name"},
+			idColumn: "code",
+			want:     "UPDATE countries SET name = $1, updated_at = now() WHERE code = $2",
+		},
+		{
+			table:    "one",
+			fields:   []string{"one_field"},
+			idColumn: "",
+			want:     "UPDATE one SET one_field = $1, updated_at = now() WHERE id = $2",
+		},
+		{
+			table:    "nothing",
+			fields:   []string{},
+			idColumn: "uuid",
+			want:     ErrFieldsAreEmpty,
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLUpdateByColumn(tt.table, tt.fields, tt.idColumn))
+	}
+}
+
+func TestBuildSQLSet(t *testing.T) {
+	tableTest := []struct {
+		name          string
+		fields        []string
+		startParam    int
+		want          string
+		wantNextParam int
+	}{
+		{
+			name:          "several fields",
+			fields:        []string{"responsable", "country", "user_id"},
+			startParam:    1,
+			want:          "responsable = $1, country = $2, user_id = $3",
+			wantNextParam: 4,
+		},
+		{
+			name:          "one field starting in other param",
+			fields:        []string{"one_field"},
+			startParam:    3,
+			want:          "one_field = $3",
+			wantNextParam: 4,
+		},
+		{
+			name:          "empty fields",
+			fields:        []string{},
+			startParam:    1,
+			want:          ErrFieldsAreEmpty,
+			wantNextParam: 1,
+		},
+	}
+
+	for _, tt := range tableTest {
+		got, gotNextParam := BuildSQLSet(tt.fields, tt.startParam)
+		assert.Equal(t, tt.want, got, tt.name)
+		assert.Equal(t, tt.wantNextParam, gotNextParam, tt.name)
+	}
+}
+
 func TestBuildSQLSelect(t *testing.T) {
 	tableTest := []struct {
 		table  string
@@ -121,7 +458,7 @@ func TestBuildSQLSelect(t *testing.T) {
 	}
 }
 
-func TestBuildSQLSelectFields(t *testing.T) {
+func TestBuildSQLSelectWithTotalCount(t *testing.T) {
 	tableTest := []struct {
 		table  string
 		fields []string
@@ -129,144 +466,427 @@ func TestBuildSQLSelectFields(t *testing.T) {
 	}{
 		{
 			table:  "cashboxes",
-			fields: []string{"responsable", "country", "user_id", "account"},
-			want:   "SELECT responsable, country, user_id, account FROM cashboxes",
+			fields: []string{"responsable", "country"},
+			want:   "SELECT id, responsable, country, created_at, updated_at, COUNT(*) OVER() AS total_count FROM cashboxes",
 		},
 		{
 			table:  "nothing",
 			fields: []string{},
 			want:   ErrFieldsAreEmpty,
 		},
-		{
-			table:  "one",
-			fields: []string{"one_field"},
-			want:   "SELECT one_field FROM one",
-		},
 	}
 
 	for _, tt := range tableTest {
-		assert.Equal(t, tt.want, BuildSQLSelectFields(tt.table, tt.fields))
+		assert.Equal(t, tt.want, BuildSQLSelectWithTotalCount(tt.table, tt.fields))
 	}
-}
 
-func TestBuildSQLWhere(t *testing.T) {
-	fakeDate := time.Date(2021, 4, 28, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	gotQuery, gotArgs := BuildQueryArgsAndPagination(
+		BuildSQLSelectWithTotalCount("cashboxes", []string{"country"}),
+		models.Fields{{Name: "country", Value: "COLOMBIA"}},
+		models.SortFields{{Name: "id"}},
+		models.Pagination{Page: 2, Limit: 10},
+	)
+	assert.Equal(t, "SELECT id, country, created_at, updated_at, COUNT(*) OVER() AS total_count FROM cashboxes WHERE country = $1 ORDER BY id ASC LIMIT 10 OFFSET 10", gotQuery)
+	assert.Equal(t, []interface{}{"COLOMBIA"}, gotArgs)
+}
 
+func TestBuildSQLSelectDistinct(t *testing.T) {
 	tableTest := []struct {
-		name      string
-		fields    models.Fields
-		wantQuery string
-		wantArgs  []interface{}
+		table  string
+		fields []string
+		want   string
 	}{
 		{
-			name:      "where with emtpy fields",
-			fields:    models.Fields{},
-			wantQuery: "",
-			wantArgs:  nil,
+			table:  "cashboxes",
+			fields: []string{"country", "account"},
+			want:   "SELECT DISTINCT id, country, account, created_at, updated_at FROM cashboxes",
 		},
 		{
-			name: "where with ILIKE",
-			fields: models.Fields{
-				{Name: "id", Value: []uint{1, 2, 3}, Operator: models.In},
-			},
-			wantQuery: "WHERE id IN (1,2,3)",
-			wantArgs:  nil,
+			table:  "empty",
+			fields: []string{},
+			want:   ErrFieldsAreEmpty,
 		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLSelectDistinct(tt.table, tt.fields))
+	}
+}
+
+func TestBuildSQLSelectDistinctOn(t *testing.T) {
+	tableTest := []struct {
+		name         string
+		distinctCols []string
+		fields       []string
+		want         string
+	}{
 		{
-			name: "where with all operators",
-			fields: models.Fields{
-				{Name: "name", Value: "Alejandro"},
-				{Name: "age", Value: 30, ChainingKey: models.Or},
-				{Name: "course", Value: "Go"},
-				{Name: "id", Value: []uint{1, 4, 9}, Operator: models.In},
-				{Name: "DESCRIPTION", Value: "%golang%", Operator: models.Ilike},
-				{Name: "certificates", Value: 3, Operator: models.GreaterThan},
-				{Name: "is_active", Value: true},
-			},
-			wantQuery: "WHERE name = $1 AND age = $2 OR course = $3 AND id IN (1,4,9) AND description ILIKE $4 AND certificates > $5 AND is_active = $6",
-			wantArgs:  []interface{}{"Alejandro", 30, "Go", "%golang%", 3, true},
+			name:         "distinct on two columns",
+			distinctCols: []string{"user_id", "country"},
+			fields:       []string{"user_id", "country", "amount"},
+			want:         "SELECT DISTINCT ON (user_id, country) id, user_id, country, amount, created_at, updated_at FROM payments",
 		},
 		{
-			name: "where with operators and string ILIKE",
-			fields: models.Fields{
-				{Name: "country", Value: "COLOMBIA"},
-				{Name: "currency_id", Value: 3, ChainingKey: models.Or},
-				{Name: "enable", Value: true},
-				{Name: "code", Value: []string{"COL", "COP"}, Operator: models.In},
-			},
-			wantQuery: "WHERE country = $1 AND currency_id = $2 OR enable = $3 AND code IN ('COL','COP')",
-			wantArgs:  []interface{}{"COLOMBIA", 3, true},
+			name:         "empty distinct columns",
+			distinctCols: []string{},
+			fields:       []string{"user_id"},
+			want:         ErrFieldsAreEmpty,
 		},
 		{
-			name: "where with operators and NOT NULL",
-			fields: models.Fields{
-				{Name: "country", Value: "COLOMBIA"},
-				{Name: "currency_id", Value: 3, ChainingKey: models.Or},
-				{Name: "begins_at", Value: "fake", Operator: models.IsNull},
-				{Name: "enable", Value: true},
-				{Name: "code", Value: []string{"COL", "COP"}, Operator: models.In},
-			},
-			wantQuery: "WHERE country = $1 AND currency_id = $2 OR begins_at IS NULL AND enable = $3 AND code IN ('COL','COP')",
-			wantArgs:  []interface{}{"COLOMBIA", 3, true},
+			name:         "empty fields",
+			distinctCols: []string{"user_id"},
+			fields:       []string{},
+			want:         ErrFieldsAreEmpty,
 		},
 		{
-			name: "where with aliased",
-			fields: models.Fields{
-				{Source: "contracts", Name: "employer_id", Value: 777},
-				{Source: "contracts", Name: "pay_frequency_id", Value: 2, ChainingKey: models.Or},
-				{Source: "contracts", Name: "is_active", Value: true},
-				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
-			},
-			wantQuery: "WHERE contracts.employer_id = $1 AND contracts.pay_frequency_id = $2 OR contracts.is_active = $3 AND contract_statuses.description ILIKE $4",
-			wantArgs:  []interface{}{777, 2, true, "ACTIVE"},
+			name:         "invalid distinct column",
+			distinctCols: []string{"user_id) id, (SELECT 1"},
+			fields:       []string{"user_id"},
+			want:         ErrInvalidIdentifier,
 		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLSelectDistinctOn("payments", tt.distinctCols, tt.fields), tt.name)
+	}
+}
+
+func TestValidateDistinctOnOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		distinctCols []string
+		order        models.SortFields
+		wantErr      error
+	}{
 		{
-			name: "where with aliased on two tables with upper case",
-			fields: models.Fields{
-				{Source: "contracts", Name: "employer_id", Value: 777},
-				{Source: "contracts", Name: "pay_frequency_id", Value: 2, ChainingKey: models.Or},
-				{Source: "contracts", Name: "endS_at", Operator: models.LessThan, IsValueFromTable: true, SourceNameValueFromTable: "peRiods", NameValueFromTable: "eNds_at"},
-				{Source: "contracts", Name: "is_active", Value: true},
-				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
-			},
-			wantQuery: "WHERE contracts.employer_id = $1 AND contracts.pay_frequency_id = $2 OR contracts.ends_at < periods.ends_at AND contracts.is_active = $3 AND contract_statuses.description ILIKE $4",
-			wantArgs:  []interface{}{777, 2, true, "ACTIVE"},
+			name:         "aligned",
+			distinctCols: []string{"user_id"},
+			order:        models.SortFields{{Name: "user_id"}, {Name: "created_at", Order: models.Desc}},
 		},
 		{
-			name: "where with aliased where some fields have missing source",
-			fields: models.Fields{
-				{Name: "employer_id", Value: 19},
-				{Name: "pay_frequency_id", Value: 1, ChainingKey: models.Or},
-				{Name: "is_active", Value: false},
-				{Source: "contract_statuses", Name: "description", Value: "CREATED", Operator: models.Ilike},
-			},
-			wantQuery: "WHERE employer_id = $1 AND pay_frequency_id = $2 OR is_active = $3 AND contract_statuses.description ILIKE $4",
-			wantArgs:  []interface{}{19, 1, false, "CREATED"},
+			name:         "aligned with other order and aliases",
+			distinctCols: []string{"p.user_id", "p.Country"},
+			order:        models.SortFields{{Source: "p", Name: "country"}, {Source: "p", Name: "user_id"}, {Name: "created_at"}},
 		},
 		{
-			name: "where with group conditions",
-			fields: models.Fields{
-				{Name: "employer_id", Value: 1},
-				{Name: "pay_frequency_id", Value: 2},
-				{GroupOpen: true, Name: "is_active", Value: true, ChainingKey: models.Or},
-				{GroupClose: true, Name: "is_staff", Value: false},
-				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
-			},
-			wantQuery: "WHERE employer_id = $1 AND pay_frequency_id = $2 AND (is_active = $3 OR is_staff = $4) AND contract_statuses.description ILIKE $5",
-			wantArgs:  []interface{}{1, 2, true, false, "ACTIVE"},
+			name:         "misaligned",
+			distinctCols: []string{"user_id"},
+			order:        models.SortFields{{Name: "created_at", Order: models.Desc}, {Name: "user_id"}},
+			wantErr:      models.ErrDistinctOnOrderMissMatch,
 		},
 		{
-			name: "where with group conditions and with missing GroupClose key",
-			fields: models.Fields{
-				{Name: "employer_id", Value: 1},
-				{Name: "pay_frequency_id", Value: 2},
-				{GroupOpen: true, Name: "is_active", Value: true, ChainingKey: models.Or},
-				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
-			},
-			wantQuery: "WHERE employer_id = $1 AND pay_frequency_id = $2 AND (is_active = $3 OR contract_statuses.description ILIKE $4)",
-			wantArgs:  []interface{}{1, 2, true, "ACTIVE"},
+			name:         "order shorter than distinct columns",
+			distinctCols: []string{"user_id", "country"},
+			order:        models.SortFields{{Name: "user_id"}},
+			wantErr:      models.ErrDistinctOnOrderMissMatch,
 		},
-		{
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, ValidateDistinctOnOrder(tt.distinctCols, tt.order), tt.wantErr)
+		})
+	}
+}
+
+func TestBuildSQLSelectByIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		table     string
+		fields    []string
+		ids       []uint
+		order     models.SortFields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "ids with order",
+			table:     "users",
+			fields:    []string{"name", "email"},
+			ids:       []uint{8, 3, 5},
+			order:     models.SortFields{{Name: "name"}, {Name: "id", Order: models.Desc}},
+			wantQuery: "SELECT id, name, email, created_at, updated_at FROM users WHERE id IN ($1, $2, $3) ORDER BY name ASC, id DESC",
+			wantArgs:  []interface{}{uint(8), uint(3), uint(5)},
+		},
+		{
+			name:      "ids without order",
+			table:     "users",
+			fields:    []string{"name"},
+			ids:       []uint{1},
+			wantQuery: "SELECT id, name, created_at, updated_at FROM users WHERE id IN ($1)",
+			wantArgs:  []interface{}{uint(1)},
+		},
+		{
+			name:      "without ids",
+			table:     "users",
+			fields:    []string{"name"},
+			ids:       []uint{},
+			wantQuery: "SELECT id, name, created_at, updated_at FROM users WHERE id = 0",
+			wantArgs:  nil,
+		},
+		{
+			name:      "empty fields",
+			table:     "users",
+			fields:    []string{},
+			ids:       []uint{1},
+			wantQuery: ErrFieldsAreEmpty,
+			wantArgs:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLSelectByIDs(tt.table, tt.fields, tt.ids, tt.order)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLSelectWithDeletedAt(t *testing.T) {
+	tableTest := []struct {
+		table  string
+		fields []string
+		want   string
+	}{
+		{
+			table:  "cashboxes",
+			fields: []string{"responsable", "country", "user_id", "account"},
+			want:   "SELECT id, responsable, country, user_id, account, created_at, updated_at, deleted_at FROM cashboxes",
+		},
+		{
+			table:  "nothing",
+			fields: []string{},
+			want:   ErrFieldsAreEmpty,
+		},
+		{
+			table:  "one",
+			fields: []string{"one_field"},
+			want:   "SELECT id, one_field, created_at, updated_at, deleted_at FROM one",
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLSelectWithDeletedAt(tt.table, tt.fields))
+	}
+}
+
+func TestBuildSQLSelectActive(t *testing.T) {
+	tableTest := []struct {
+		table  string
+		fields []string
+		want   string
+	}{
+		{
+			table:  "cashboxes",
+			fields: []string{"responsable", "country", "user_id", "account"},
+			want:   "SELECT id, responsable, country, user_id, account, created_at, updated_at FROM cashboxes WHERE deleted_at IS NULL",
+		},
+		{
+			table:  "nothing",
+			fields: []string{},
+			want:   ErrFieldsAreEmpty,
+		},
+		{
+			table:  "one",
+			fields: []string{"one_field"},
+			want:   "SELECT id, one_field, created_at, updated_at FROM one WHERE deleted_at IS NULL",
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLSelectActive(tt.table, tt.fields))
+	}
+}
+
+func TestBuildSQLSelectFields(t *testing.T) {
+	tableTest := []struct {
+		table  string
+		fields []string
+		want   string
+	}{
+		{
+			table:  "cashboxes",
+			fields: []string{"responsable", "country", "user_id", "account"},
+			want:   "SELECT responsable, country, user_id, account FROM cashboxes",
+		},
+		{
+			table:  "nothing",
+			fields: []string{},
+			want:   ErrFieldsAreEmpty,
+		},
+		{
+			table:  "one",
+			fields: []string{"one_field"},
+			want:   "SELECT one_field FROM one",
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLSelectFields(tt.table, tt.fields))
+	}
+}
+
+func TestBuildSQLSelectInto(t *testing.T) {
+	tableTest := []struct {
+		newTable    string
+		sourceTable string
+		fields      []string
+		want        string
+	}{
+		{
+			newTable:    "contracts_2021",
+			sourceTable: "contracts",
+			fields:      []string{"id", "employer_id", "hire_date"},
+			want:        "SELECT id, employer_id, hire_date INTO contracts_2021 FROM contracts",
+		},
+		{
+			newTable:    "backups.contracts",
+			sourceTable: "contracts",
+			fields:      []string{"id"},
+			want:        "SELECT id INTO backups.contracts FROM contracts",
+		},
+		{
+			newTable:    "contracts; DROP TABLE users",
+			sourceTable: "contracts",
+			fields:      []string{"id"},
+			want:        ErrInvalidIdentifier,
+		},
+		{
+			newTable:    "contracts_2021",
+			sourceTable: "contracts",
+			fields:      []string{},
+			want:        ErrFieldsAreEmpty,
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLSelectInto(tt.newTable, tt.sourceTable, tt.fields))
+	}
+}
+
+func TestBuildSQLWhere(t *testing.T) {
+	fakeDate := time.Date(2021, 4, 28, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "where with emtpy fields",
+			fields:    models.Fields{},
+			wantQuery: "",
+			wantArgs:  nil,
+		},
+		{
+			name:      "where with nil fields",
+			fields:    nil,
+			wantQuery: "",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with ILIKE",
+			fields: models.Fields{
+				{Name: "id", Value: []uint{1, 2, 3}, Operator: models.In},
+			},
+			wantQuery: "WHERE id IN (1,2,3)",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with all operators",
+			fields: models.Fields{
+				{Name: "name", Value: "Alejandro"},
+				{Name: "age", Value: 30, ChainingKey: models.Or},
+				{Name: "course", Value: "Go"},
+				{Name: "id", Value: []uint{1, 4, 9}, Operator: models.In},
+				{Name: "DESCRIPTION", Value: "%golang%", Operator: models.Ilike},
+				{Name: "certificates", Value: 3, Operator: models.GreaterThan},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE name = $1 AND age = $2 OR course = $3 AND id IN (1,4,9) AND description ILIKE $4 AND certificates > $5 AND is_active = $6",
+			wantArgs:  []interface{}{"Alejandro", 30, "Go", "%golang%", 3, true},
+		},
+		{
+			name: "where with operators and string ILIKE",
+			fields: models.Fields{
+				{Name: "country", Value: "COLOMBIA"},
+				{Name: "currency_id", Value: 3, ChainingKey: models.Or},
+				{Name: "enable", Value: true},
+				{Name: "code", Value: []string{"COL", "COP"}, Operator: models.In},
+			},
+			wantQuery: "WHERE country = $1 AND currency_id = $2 OR enable = $3 AND code IN ($4, $5)",
+			wantArgs:  []interface{}{"COLOMBIA", 3, true, "COL", "COP"},
+		},
+		{
+			name: "where with operators and NOT NULL",
+			fields: models.Fields{
+				{Name: "country", Value: "COLOMBIA"},
+				{Name: "currency_id", Value: 3, ChainingKey: models.Or},
+				{Name: "begins_at", Value: "fake", Operator: models.IsNull},
+				{Name: "enable", Value: true},
+				{Name: "code", Value: []string{"COL", "COP"}, Operator: models.In},
+			},
+			wantQuery: "WHERE country = $1 AND currency_id = $2 OR begins_at IS NULL AND enable = $3 AND code IN ($4, $5)",
+			wantArgs:  []interface{}{"COLOMBIA", 3, true, "COL", "COP"},
+		},
+		{
+			name: "where with aliased",
+			fields: models.Fields{
+				{Source: "contracts", Name: "employer_id", Value: 777},
+				{Source: "contracts", Name: "pay_frequency_id", Value: 2, ChainingKey: models.Or},
+				{Source: "contracts", Name: "is_active", Value: true},
+				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
+			},
+			wantQuery: "WHERE contracts.employer_id = $1 AND contracts.pay_frequency_id = $2 OR contracts.is_active = $3 AND contract_statuses.description ILIKE $4",
+			wantArgs:  []interface{}{777, 2, true, "ACTIVE"},
+		},
+		{
+			name: "where with aliased on two tables with upper case",
+			fields: models.Fields{
+				{Source: "contracts", Name: "employer_id", Value: 777},
+				{Source: "contracts", Name: "pay_frequency_id", Value: 2, ChainingKey: models.Or},
+				{Source: "contracts", Name: "endS_at", Operator: models.LessThan, IsValueFromTable: true, SourceNameValueFromTable: "peRiods", NameValueFromTable: "eNds_at"},
+				{Source: "contracts", Name: "is_active", Value: true},
+				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
+			},
+			wantQuery: "WHERE contracts.employer_id = $1 AND contracts.pay_frequency_id = $2 OR contracts.ends_at < periods.ends_at AND contracts.is_active = $3 AND contract_statuses.description ILIKE $4",
+			wantArgs:  []interface{}{777, 2, true, "ACTIVE"},
+		},
+		{
+			name: "where with aliased where some fields have missing source",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 19},
+				{Name: "pay_frequency_id", Value: 1, ChainingKey: models.Or},
+				{Name: "is_active", Value: false},
+				{Source: "contract_statuses", Name: "description", Value: "CREATED", Operator: models.Ilike},
+			},
+			wantQuery: "WHERE employer_id = $1 AND pay_frequency_id = $2 OR is_active = $3 AND contract_statuses.description ILIKE $4",
+			wantArgs:  []interface{}{19, 1, false, "CREATED"},
+		},
+		{
+			name: "where with group conditions",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "pay_frequency_id", Value: 2},
+				{GroupOpen: true, Name: "is_active", Value: true, ChainingKey: models.Or},
+				{GroupClose: true, Name: "is_staff", Value: false},
+				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
+			},
+			wantQuery: "WHERE employer_id = $1 AND pay_frequency_id = $2 AND (is_active = $3 OR is_staff = $4) AND contract_statuses.description ILIKE $5",
+			wantArgs:  []interface{}{1, 2, true, false, "ACTIVE"},
+		},
+		{
+			name: "where with group conditions and with missing GroupClose key",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "pay_frequency_id", Value: 2},
+				{GroupOpen: true, Name: "is_active", Value: true, ChainingKey: models.Or},
+				{Source: "contract_statuses", Name: "description", Value: "ACTIVE", Operator: models.Ilike},
+			},
+			wantQuery: "WHERE employer_id = $1 AND pay_frequency_id = $2 AND (is_active = $3 OR contract_statuses.description ILIKE $4)",
+			wantArgs:  []interface{}{1, 2, true, "ACTIVE"},
+		},
+		{
 			name: "where with group conditions and aliases - complex",
 			fields: models.Fields{
 				{Source: "c", Name: "employer_id", Value: 1},
@@ -302,154 +922,1766 @@ func TestBuildSQLWhere(t *testing.T) {
 			wantArgs:  []interface{}{1, 2, "ACTIVE", "CREATED", "2021-04-28"},
 		},
 		{
-			name: "where with BETWEEN",
+			name: "where with a CTE as source",
 			fields: models.Fields{
-				{Name: "begins_at", Operator: models.Between, FromValue: parseToDate(2010, 5, 3), ToValue: parseToDate(2020, 1, 1)},
+				{Source: "active_contracts", Name: "employer_id", Value: 7},
+				{Source: "active_contracts", Name: "ends_at", Operator: models.GreaterThanOrEqualTo, IsValueFromTable: true, SourceNameValueFromTable: "pp", NameValueFromTable: "ends_at"},
 			},
-			wantQuery: "WHERE begins_at BETWEEN $1 AND $2",
-			wantArgs:  []interface{}{parseToDate(2010, 5, 3), parseToDate(2020, 1, 1)},
+			wantQuery: "WHERE active_contracts.employer_id = $1 AND active_contracts.ends_at >= pp.ends_at",
+			wantArgs:  []interface{}{7},
 		},
 		{
-			name: "where with group conditions and aliases and between - complex",
+			name: "where with enum cast",
 			fields: models.Fields{
-				{Source: "c", Name: "employer_id", Value: 1},
-				{Source: "c", Name: "ends_at", IsValueFromTable: true, SourceNameValueFromTable: "pp", NameValueFromTable: "ends_at"},
-				{Source: "c", Name: "termination_date", Operator: models.IsNotNull},
-				{Source: "c", Name: "pay_frequency_id", Value: 2},
-				{GroupOpen: true, Source: "cs", Name: "description", Operator: models.Ilike, Value: "ACTIVE", ChainingKey: models.Or},
-				{Source: "c", Name: "frequency", Operator: models.GreaterThanOrEqualTo, IsValueFromTable: true, SourceNameValueFromTable: "s", NameValueFromTable: "months"},
-				{Source: "c", Name: "begins_at", Operator: models.Between, FromValue: parseToDate(2020, 1, 1), ToValue: parseToDate(2021, 12, 31)},
-				{GroupOpen: true, Source: "cs", Name: "description", Operator: models.Ilike, Value: "CREATED"},
-				{GroupClose: true, Source: "c", Name: "hire_date", Operator: models.LessThanOrEqualTo, Value: fakeDate},
+				{Name: "user_id", Value: 5},
+				{Name: "status", Value: "active", EnumType: "order_status"},
+				{Name: "channel", Value: "web", Operator: models.NotEqualTo, EnumType: "order_channel"},
+			},
+			wantQuery: "WHERE user_id = $1 AND status = $2::order_status AND channel <> $3::order_channel",
+			wantArgs:  []interface{}{5, "active", "web"},
+		},
+		{
+			name: "where with an invalid enum cast",
+			fields: models.Fields{
+				{Name: "status", Value: "active", EnumType: "t; DROP TABLE x"},
+			},
+			wantQuery: "invalid enum type: t; DROP TABLE x",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a placeholder written in the name",
+			fields: models.Fields{
+				{Name: "amount + $2", Value: 100, Operator: models.GreaterThan},
+			},
+			wantQuery: "placeholders and args are missmatch: 2 placeholders and 1 args",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with column to column comparisons inside groups",
+			fields: models.Fields{
+				{Source: "c", Name: "employer_id", Value: 1},
+				{GroupOpen: true, Source: "c", Name: "ends_at", Operator: models.NotEqualTo, IsValueFromTable: true, SourceNameValueFromTable: "pp", NameValueFromTable: "ends_at", ChainingKey: models.Or},
+				{GroupClose: true, Source: "c", Name: "begins_at", Operator: models.LessThanOrEqualTo, IsValueFromTable: true, SourceNameValueFromTable: "pp", NameValueFromTable: "begins_at"},
+				{GroupOpen: true, Source: "c", Name: "salary", Operator: models.GreaterThan, IsValueFromTable: true, SourceNameValueFromTable: "s", NameValueFromTable: "minimum"},
+				{Source: "c", Name: "salary", Operator: models.LessThan, IsValueFromTable: true, SourceNameValueFromTable: "s", NameValueFromTable: "maximum"},
+				{GroupClose: true, Source: "c", Name: "bonus", Operator: models.GreaterThanOrEqualTo, IsValueFromTable: true, SourceNameValueFromTable: "s", NameValueFromTable: "bonus"},
+				{Source: "c", Name: "currency_id", Operator: models.Equals, IsValueFromTable: true, SourceNameValueFromTable: "s", NameValueFromTable: "currency_id"},
+				{Source: "c", Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE c.employer_id = $1 AND (c.ends_at <> pp.ends_at OR c.begins_at <= pp.begins_at) AND (c.salary > s.minimum AND c.salary < s.maximum AND c.bonus >= s.bonus) AND c.currency_id = s.currency_id AND c.is_active = $2",
+			wantArgs:  []interface{}{1, true},
+		},
+		{
+			name: "where with unaccent search",
+			fields: models.Fields{
+				{Name: "is_active", Value: true},
+				{GroupOpen: true, Name: "Name", Value: "%jose%", Operator: models.Ilike, Unaccent: true, ChainingKey: models.Or},
+				{GroupClose: true, Source: "c", Name: "city", Value: "%bogota%", Operator: models.Ilike, Unaccent: true},
+			},
+			wantQuery: "WHERE is_active = $1 AND (unaccent(name) ILIKE unaccent($2) OR unaccent(c.city) ILIKE unaccent($3))",
+			wantArgs:  []interface{}{true, "%jose%", "%bogota%"},
+		},
+		{
+			name: "where with case-insensitive IN opening a group",
+			fields: models.Fields{
+				{GroupOpen: true, Name: "Code", Value: []string{"COL"}, Operator: models.In, IgnoreCase: true, ChainingKey: models.Or},
+				{GroupClose: true, Name: "code", Operator: models.IsNull},
+			},
+			wantQuery: "WHERE (lower(code) IN ($1) OR code IS NULL)",
+			wantArgs:  []interface{}{"col"},
+		},
+		{
+			name: "where with BETWEEN",
+			fields: models.Fields{
+				{Name: "begins_at", Operator: models.Between, FromValue: parseToDate(2010, 5, 3), ToValue: parseToDate(2020, 1, 1)},
+			},
+			wantQuery: "WHERE begins_at BETWEEN $1 AND $2",
+			wantArgs:  []interface{}{parseToDate(2010, 5, 3), parseToDate(2020, 1, 1)},
+		},
+		{
+			name: "where with BETWEEN SYMMETRIC followed by a field",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "salary", Operator: models.BetweenSymmetric, FromValue: 5000, ToValue: 1000},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND salary BETWEEN SYMMETRIC $2 AND $3 AND is_active = $4",
+			wantArgs:  []interface{}{1, 5000, 1000, true},
+		},
+		{
+			name: "where with NOT BETWEEN followed by a field",
+			fields: models.Fields{
+				{Name: "begins_at", Operator: models.NotBetween, FromValue: parseToDate(2010, 5, 3), ToValue: parseToDate(2020, 1, 1)},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE begins_at NOT BETWEEN $1 AND $2 AND is_active = $3",
+			wantArgs:  []interface{}{parseToDate(2010, 5, 3), parseToDate(2020, 1, 1), true},
+		},
+		{
+			name: "where with an IN subquery between parameterized fields",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "id", Operator: models.In, RawSubquery: "SELECT user_id FROM orders WHERE total > 100"},
+				{Name: "code", Operator: models.NotIn, RawSubquery: "SELECT code FROM blocked_codes"},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND id IN (SELECT user_id FROM orders WHERE total > 100) AND code NOT IN (SELECT code FROM blocked_codes) AND is_active = $2",
+			wantArgs:  []interface{}{1, true},
+		},
+		{
+			name: "where with EXISTS and NOT EXISTS in a group",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Operator: models.Exists, RawSubquery: "SELECT 1 FROM contracts c WHERE c.user_id = u.id", ChainingKey: models.Or, GroupOpen: true},
+				{Operator: models.NotExists, RawSubquery: "SELECT 1 FROM bans b WHERE b.user_id = u.id", ChainingKey: models.Or},
+				{Name: "is_admin", Value: true, GroupClose: true},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND (EXISTS (SELECT 1 FROM contracts c WHERE c.user_id = u.id) OR NOT EXISTS (SELECT 1 FROM bans b WHERE b.user_id = u.id) OR is_admin = $2) AND is_active = $3",
+			wantArgs:  []interface{}{1, true, true},
+		},
+		{
+			name: "where with ANY and ALL",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "status", Operator: models.Any, Value: []string{"active", "pending"}},
+				{Name: "score", Operator: models.All, ArrayComparison: models.GreaterThan, Value: []int{10, 20}},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND status = ANY($2) AND score > ALL($3) AND is_active = $4",
+			wantArgs:  []interface{}{1, pq.Array([]string{"active", "pending"}), pq.Array([]int{10, 20}), true},
+		},
+		{
+			name: "where with ANY of an invalid comparison",
+			fields: models.Fields{
+				{Name: "status", Operator: models.Any, ArrayComparison: models.Like, Value: []string{"a%"}},
+			},
+			wantQuery: "invalid comparison of ANY or ALL: LIKE",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a jsonb containment and an equality",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "metadata", Operator: models.JSONContains, Value: map[string]interface{}{"plan": "pro"}},
+				{Name: "metadata", Operator: models.JSONContainedBy, Value: `{"plan": "pro", "seats": 5}`},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND metadata @> $2 AND metadata <@ $3 AND is_active = $4",
+			wantArgs:  []interface{}{1, `{"plan":"pro"}`, `{"plan": "pro", "seats": 5}`, true},
+		},
+		{
+			name: "where with a jsonb key",
+			fields: models.Fields{
+				{Name: "metadata", Operator: models.JSONHasKey, Value: "plan"},
+				{Name: "Metadata", JSONKey: "plan", Value: "pro"},
+				{Name: "metadata", JSONKey: "owner's", Operator: models.NotEqualTo, Value: "x"},
+			},
+			wantQuery: "WHERE metadata ? $1 AND metadata->>'plan' = $2 AND metadata->>'owner''s' <> $3",
+			wantArgs:  []interface{}{"plan", "pro", "x"},
+		},
+		{
+			name: "where with a jsonb containment of an invalid json",
+			fields: models.Fields{
+				{Name: "metadata", Operator: models.JSONContains, Value: make(chan int)},
+			},
+			wantQuery: "json: unsupported type: chan int",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a raw condition between parameterized fields",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Raw: "age(birth_date) > interval '18 years'"},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND age(birth_date) > interval '18 years' AND is_active = $2",
+			wantArgs:  []interface{}{1, true},
+		},
+		{
+			name: "where with a raw condition in a group",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "is_admin", Value: true, ChainingKey: models.Or, GroupOpen: true},
+				{Raw: "lower(email) LIKE '%@example.com'", GroupClose: true},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND (is_admin = $2 OR lower(email) LIKE '%@example.com') AND is_active = $3",
+			wantArgs:  []interface{}{1, true, true},
+		},
+		{
+			name: "where with a raw condition with args",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Raw: "coalesce(discount, ?) > ?", Args: []interface{}{0, 15.5}},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND coalesce(discount, $2) > $3 AND is_active = $4",
+			wantArgs:  []interface{}{1, 0, 15.5, true},
+		},
+		{
+			name: "where with a raw condition with a quoted question mark",
+			fields: models.Fields{
+				{Raw: "name <> '?' AND age > ?", Args: []interface{}{18}},
+			},
+			wantQuery: "WHERE name <> '?' AND age > $1",
+			wantArgs:  []interface{}{18},
+		},
+		{
+			name: "where with a raw condition with an escaped question mark",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Raw: "data ?? 'plan' AND seats > ?", Args: []interface{}{5}},
+			},
+			wantQuery: "WHERE employer_id = $1 AND data ? 'plan' AND seats > $2",
+			wantArgs:  []interface{}{1, 5},
+		},
+		{
+			name: "where with a raw condition with an escaped question mark without args",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Raw: "data ?? 'plan'"},
+			},
+			wantQuery: "WHERE employer_id = $1 AND data ? 'plan'",
+			wantArgs:  []interface{}{1},
+		},
+		{
+			name: "where with a raw condition with a marker without args",
+			fields: models.Fields{
+				{Raw: "data ? 'plan'"},
+			},
+			wantQuery: "placeholders and args are missmatch: the raw condition has 1 markers and 0 args",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a raw condition with missing args",
+			fields: models.Fields{
+				{Raw: "coalesce(discount, ?) > ?", Args: []interface{}{0}},
+			},
+			wantQuery: "placeholders and args are missmatch: the raw condition has 2 markers and 1 args",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with EXISTS without subquery",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Operator: models.Exists},
+			},
+			wantQuery: "the subquery is empty: the operator EXISTS",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a reversed BETWEEN",
+			fields: models.Fields{
+				{Name: "salary", Operator: models.Between, FromValue: 5000, ToValue: 1000},
+			},
+			wantQuery: models.ErrFromIsGreaterThanTo.Error(),
+			wantArgs:  nil,
+		},
+		{
+			name: "where with NOT BETWEEN without the from value",
+			fields: models.Fields{
+				{Name: "salary", Operator: models.NotBetween, ToValue: 1000},
+			},
+			wantQuery: models.ErrFromValueIsEmpty.Error(),
+			wantArgs:  nil,
+		},
+		{
+			name: "where with BETWEEN two columns",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Source: "c", Name: "hire_date", Operator: models.Between, IsValueFromTable: true, SourceNameValueFromTable: "pp", FromValue: "Begins_at", ToValue: "ends_at"},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND c.hire_date BETWEEN pp.begins_at AND pp.ends_at AND is_active = $2",
+			wantArgs:  []interface{}{1, true},
+		},
+		{
+			name: "where with BETWEEN two columns that are not names",
+			fields: models.Fields{
+				{Name: "hire_date", Operator: models.Between, IsValueFromTable: true, FromValue: 1, ToValue: 2},
+			},
+			wantQuery: models.ErrFromAndToValuesAreNotColumns.Error(),
+			wantArgs:  nil,
+		},
+		{
+			name: "where with NOT IN",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "id", Operator: models.NotIn, Value: []int{4, 5}},
+				{Name: "code", Operator: models.NotIn, Value: []string{"COL", "COP"}},
+			},
+			wantQuery: "WHERE employer_id = $1 AND id NOT IN (4,5) AND code NOT IN ($2, $3)",
+			wantArgs:  []interface{}{1, "COL", "COP"},
+		},
+		{
+			name: "where with LIKE, NOT LIKE and NOT ILIKE mixed with other operators",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "code", Operator: models.Like, Value: "AB%"},
+				{Name: "status", Operator: models.IsNotNull},
+				{Name: "name", Operator: models.NotLike, Value: "%Test%", ChainingKey: models.Or},
+				{Name: "email", Operator: models.NotIlike, Value: "%@example.com"},
+				{Name: "amount", Operator: models.GreaterThan, Value: 100},
+			},
+			wantQuery: "WHERE employer_id = $1 AND code LIKE $2 AND status IS NOT NULL AND name NOT LIKE $3 OR email NOT ILIKE $4 AND amount > $5",
+			wantArgs:  []interface{}{1, "AB%", "%Test%", "%@example.com", 100},
+		},
+		{
+			name: "where with the LIKE operators ~~, ~~*, !~~ and !~~*",
+			fields: models.Fields{
+				{Name: "code", Operator: models.LikeOperator, Value: "AB%"},
+				{Name: "name", Operator: models.IlikeOperator, Value: "%ana%"},
+				{Name: "email", Operator: models.NotLikeOperator, Value: "%@test.com"},
+				{Name: "city", Operator: models.NotIlikeOperator, Value: "bog%", Escape: true},
+			},
+			wantQuery: "WHERE code ~~ $1 AND name ~~* $2 AND email !~~ $3 AND city !~~* $4",
+			wantArgs:  []interface{}{"AB%", "%ana%", "%@test.com", "bog%"},
+		},
+		{
+			name: "where with ILIKE and an escape clause",
+			fields: models.Fields{
+				{Name: "code", Operator: models.Ilike, Value: `%50\%%`, Escape: true},
+				{Name: "name", Operator: models.NotIlike, Value: `a\_b%`, Escape: true},
+			},
+			wantQuery: `WHERE code ILIKE $1 ESCAPE '\' AND name NOT ILIKE $2 ESCAPE '\'`,
+			wantArgs:  []interface{}{`%50\%%`, `a\_b%`},
+		},
+		{
+			name: "where with escape ignored in a non pattern operator",
+			fields: models.Fields{
+				{Name: "code", Value: "50%", Escape: true},
+			},
+			wantQuery: "WHERE code = $1",
+			wantArgs:  []interface{}{"50%"},
+		},
+		{
+			name: "where with a half-open range",
+			fields: append(models.Fields{
+				{Name: "employer_id", Value: 1, ChainingKey: models.Or},
+			}, models.HalfOpenRange("created_at", parseToDate(2021, 1, 1), parseToDate(2021, 2, 1))...),
+			wantQuery: "WHERE employer_id = $1 OR (created_at >= $2 AND created_at < $3)",
+			wantArgs:  []interface{}{1, parseToDate(2021, 1, 1), parseToDate(2021, 2, 1)},
+		},
+		{
+			name: "where with a correlated subquery",
+			fields: models.Fields{
+				{Name: "status", Value: "paid"},
+				{Source: "o", Name: "amount", Operator: models.GreaterThan, Subquery: &models.ScalarSubquery{
+					Expression: "avg(o2.amount)", Table: "orders", Alias: "o2", Column: "user_id", OuterColumn: "o.user_id",
+				}},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE status = $1 AND o.amount > (SELECT avg(o2.amount) FROM orders o2 WHERE o2.user_id = o.user_id) AND is_active = $2",
+			wantArgs:  []interface{}{"paid", true},
+		},
+		{
+			name: "where with a correlated subquery with an invalid alias",
+			fields: models.Fields{
+				{Name: "amount", Operator: models.GreaterThan, Subquery: &models.ScalarSubquery{
+					Expression: "avg(amount)", Table: "orders", Alias: "o2 WHERE true) OR (1", Column: "user_id", OuterColumn: "o.user_id",
+				}},
+			},
+			wantQuery: ErrInvalidIdentifier,
+			wantArgs:  nil,
+		},
+		{
+			name: "where with AT TIME ZONE",
+			fields: models.Fields{
+				{Name: "created_at", Operator: models.GreaterThanOrEqualTo, Value: "2021-04-28", AtTimeZone: "UTC"},
+				{Source: "c", Name: "ends_at", Operator: models.Between, FromValue: "2021-04-01", ToValue: "2021-04-30", AtTimeZone: "America/Bogota"},
+			},
+			wantQuery: "WHERE created_at AT TIME ZONE 'UTC' >= $1 AND c.ends_at AT TIME ZONE 'America/Bogota' BETWEEN $2 AND $3",
+			wantArgs:  []interface{}{"2021-04-28", "2021-04-01", "2021-04-30"},
+		},
+		{
+			name: "where with an invalid time zone",
+			fields: models.Fields{
+				{Name: "created_at", Value: "2021-04-28", AtTimeZone: "UTC' OR '1'='1"},
+			},
+			wantQuery: models.ErrInvalidTimeZone.Error(),
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a tsrange overlap",
+			fields: models.Fields{
+				{Name: "room_id", Value: 7},
+				models.RangeOverlap("during", parseToDate(2021, 3, 1), parseToDate(2021, 3, 2), models.TsRange),
+			},
+			wantQuery: "WHERE room_id = $1 AND during && tsrange($2, $3)",
+			wantArgs:  []interface{}{7, parseToDate(2021, 3, 1), parseToDate(2021, 3, 2)},
+		},
+		{
+			name: "where with a daterange overlap",
+			fields: models.Fields{
+				models.RangeOverlap("Vacation", "2021-12-20", "2022-01-05", models.DateRange),
+			},
+			wantQuery: "WHERE vacation && daterange($1, $2)",
+			wantArgs:  []interface{}{"2021-12-20", "2022-01-05"},
+		},
+		{
+			name: "where with an overlap of an invalid range type",
+			fields: models.Fields{
+				models.RangeOverlap("during", 1, 2, "int4range; DROP TABLE rooms"),
+			},
+			wantQuery: models.ErrInvalidRangeType.Error(),
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a tstzrange overlap of two columns",
+			fields: models.Fields{
+				{Name: "room_id", Value: 7},
+				models.ColumnsRangeOverlap("check_in", "Check_Out", parseToDate(2021, 3, 1), parseToDate(2021, 3, 5), models.TsTzRange),
+				{Name: "status", Value: "confirmed"},
+			},
+			wantQuery: "WHERE room_id = $1 AND tstzrange(check_in, check_out) && tstzrange($2, $3) AND status = $4",
+			wantArgs:  []interface{}{7, parseToDate(2021, 3, 1), parseToDate(2021, 3, 5), "confirmed"},
+		},
+		{
+			name: "where with an overlap of invalid bounds",
+			fields: models.Fields{
+				models.ColumnsRangeOverlap("check_in", "check_out", 1, 5, models.TsTzRange),
+			},
+			wantQuery: "the range bounds must be time.Time or string: int",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with an orphan group close",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1, GroupClose: true},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "a group is closed without being opened: the field employer_id",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with more group closes than group opens",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1, GroupOpen: true, ChainingKey: models.Or},
+				{Name: "is_active", Value: true, GroupClose: true},
+				{Name: "status", Value: "active", GroupClose: true},
+			},
+			wantQuery: "a group is closed without being opened: the field status",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with BETWEEN followed by a column of other table in a group",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Source: "c", Name: "hire_date", Operator: models.Between, FromValue: "2021-01-01", ToValue: "2021-12-31", GroupOpen: true},
+				{Source: "c", Name: "ends_at", Operator: models.LessThanOrEqualTo, IsValueFromTable: true, SourceNameValueFromTable: "pp", NameValueFromTable: "ends_at", GroupClose: true},
+				{Name: "is_active", Value: true},
+			},
+			wantQuery: "WHERE employer_id = $1 AND (c.hire_date BETWEEN $2 AND $3 AND c.ends_at <= pp.ends_at) AND is_active = $4",
+			wantArgs:  []interface{}{1, "2021-01-01", "2021-12-31", true},
+		},
+		{
+			name: "where with group conditions and aliases and between - complex",
+			fields: models.Fields{
+				{Source: "c", Name: "employer_id", Value: 1},
+				{Source: "c", Name: "ends_at", IsValueFromTable: true, SourceNameValueFromTable: "pp", NameValueFromTable: "ends_at"},
+				{Source: "c", Name: "termination_date", Operator: models.IsNotNull},
+				{Source: "c", Name: "pay_frequency_id", Value: 2},
+				{GroupOpen: true, Source: "cs", Name: "description", Operator: models.Ilike, Value: "ACTIVE", ChainingKey: models.Or},
+				{Source: "c", Name: "frequency", Operator: models.GreaterThanOrEqualTo, IsValueFromTable: true, SourceNameValueFromTable: "s", NameValueFromTable: "months"},
+				{Source: "c", Name: "begins_at", Operator: models.Between, FromValue: parseToDate(2020, 1, 1), ToValue: parseToDate(2021, 12, 31)},
+				{GroupOpen: true, Source: "cs", Name: "description", Operator: models.Ilike, Value: "CREATED"},
+				{GroupClose: true, Source: "c", Name: "hire_date", Operator: models.LessThanOrEqualTo, Value: fakeDate},
+			},
+			wantQuery: "WHERE c.employer_id = $1 AND c.ends_at = pp.ends_at AND c.termination_date IS NOT NULL AND c.pay_frequency_id = $2 AND (cs.description ILIKE $3 OR c.frequency >= s.months AND c.begins_at BETWEEN $4 AND $5 AND (cs.description ILIKE $6 AND c.hire_date <= $7))",
+			wantArgs:  []interface{}{1, 2, "ACTIVE", parseToDate(2020, 1, 1), parseToDate(2021, 12, 31), "CREATED", "2021-04-28"},
+		},
+	}
+
+	for _, tt := range tableTest {
+		gotQuery, gotArgs := BuildSQLWhere(tt.fields)
+		assert.Equal(t, tt.wantQuery, gotQuery, tt.name)
+		assert.Equal(t, tt.wantArgs, gotArgs, tt.name)
+	}
+}
+
+func TestBuildSQLWhere_AndGroup(t *testing.T) {
+	fields := models.Fields{
+		{Name: "tenant_id", Value: 1, ChainingKey: models.Or},
+		{Name: "is_public", Value: true},
+	}.AndGroup(models.Fields{
+		{Name: "name", Value: "Alejandro", ChainingKey: models.Or},
+		{Name: "is_admin", Value: true},
+	})
+
+	query, args := BuildSQLWhere(fields)
+	assert.Equal(t, "WHERE (tenant_id = $1 OR is_public = $2) AND (name = $3 OR is_admin = $4)", query)
+	assert.Equal(t, []interface{}{1, true, "Alejandro", true}, args)
+}
+
+func TestBuildSQLWhere_AndGroupNested(t *testing.T) {
+	system := models.Fields{{Name: "tenant_id", Value: 1}}
+
+	tests := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "half open range",
+			fields:    models.HalfOpenRange("created_at", "2021-01-01", "2021-02-01").AndGroup(system),
+			wantQuery: "WHERE ((created_at >= $1 AND created_at < $2)) AND (tenant_id = $3)",
+			wantArgs:  []interface{}{"2021-01-01", "2021-02-01", 1},
+		},
+		{
+			name: "groups in the first and the last fields",
+			fields: system.AndGroup(models.Fields{
+				{Name: "a", Value: 1, ChainingKey: models.Or, GroupOpen: true},
+				{Name: "b", Value: 2, ChainingKey: models.And, GroupClose: true},
+				{Name: "c", Value: 3, ChainingKey: models.Or, GroupOpen: true},
+				{Name: "d", Value: 4, GroupClose: true},
+			}),
+			wantQuery: "WHERE (tenant_id = $1) AND ((a = $2 OR b = $3) AND (c = $4 OR d = $5))",
+			wantArgs:  []interface{}{1, 1, 2, 3, 4},
+		},
+		{
+			name: "both sides grouped twice",
+			fields: models.HalfOpenRange("created_at", "2021-01-01", "2021-02-01").
+				AndGroup(system).
+				AndGroup(models.Fields{{Name: "is_active", Value: true, GroupOpen: true, GroupClose: true}}),
+			wantQuery: "WHERE (((created_at >= $1 AND created_at < $2)) AND (tenant_id = $3)) AND ((is_active = $4))",
+			wantArgs:  []interface{}{"2021-01-01", "2021-02-01", 1, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := BuildSQLWhere(tt.fields)
+			assert.Equal(t, tt.wantQuery, query)
+			assert.Equal(t, tt.wantArgs, args)
+		})
+	}
+}
+
+func TestBuildSQLWhereE(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+		wantErr   error
+	}{
+		{
+			name:      "valid fields",
+			fields:    models.Fields{{Name: "employer_id", Value: 1}},
+			wantQuery: "WHERE employer_id = $1",
+			wantArgs:  []interface{}{1},
+		},
+		{
+			name:    "empty fields",
+			fields:  models.Fields{},
+			wantErr: models.ErrEmptyFields,
+		},
+		{
+			name:    "BETWEEN without from value",
+			fields:  models.Fields{{Name: "begins_at", Operator: models.Between, ToValue: 1}},
+			wantErr: models.ErrFromValueIsEmpty,
+		},
+		{
+			name:    "BETWEEN with values missmatch",
+			fields:  models.Fields{{Name: "begins_at", Operator: models.Between, FromValue: 1, ToValue: "2"}},
+			wantErr: models.ErrFromAndToValuesAreMissMatch,
+		},
+	}
+
+	for _, tt := range tableTest {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs, err := BuildSQLWhereE(tt.fields)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLHaving(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "having with emtpy fields",
+			fields:    models.Fields{},
+			wantQuery: "",
+			wantArgs:  nil,
+		},
+		{
+			name: "having with one aggregate",
+			fields: models.Fields{
+				{Name: "COUNT(*)", Operator: models.GreaterThan, Value: 5},
+			},
+			wantQuery: "HAVING count(*) > $1",
+			wantArgs:  []interface{}{5},
+		},
+		{
+			name: "having with chaining keys",
+			fields: models.Fields{
+				{Name: "SUM(amount)", Operator: models.GreaterThanOrEqualTo, Value: 100, ChainingKey: models.Or},
+				{Name: "COUNT(*)", Operator: models.LessThan, Value: 3},
+			},
+			wantQuery: "HAVING sum(amount) >= $1 OR count(*) < $2",
+			wantArgs:  []interface{}{100, 3},
+		},
+		{
+			name: "having with group conditions",
+			fields: models.Fields{
+				{Name: "max(amount)", Operator: models.GreaterThan, Value: 10},
+				{Name: "count(*)", Operator: models.Between, FromValue: 1, ToValue: 5, ChainingKey: models.Or, GroupOpen: true},
+				{Name: "min(amount)", Operator: models.IsNull, GroupClose: true},
+			},
+			wantQuery: "HAVING max(amount) > $1 AND (count(*) BETWEEN $2 AND $3 OR min(amount) IS NULL)",
+			wantArgs:  []interface{}{10, 1, 5},
+		},
+		{
+			name: "having with an invalid field",
+			fields: models.Fields{
+				{Name: "count(*)", Operator: models.Between, ToValue: 5},
+			},
+			wantQuery: models.ErrFromValueIsEmpty.Error(),
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tableTest {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLHaving(tt.fields)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLWhere_ArrayIndex(t *testing.T) {
+	index := func(i int) *int { return &i }
+
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name: "array element comparison",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Source: "p", Name: "Tags", ArrayIndex: index(1), Value: "go"},
+			},
+			wantQuery: "WHERE employer_id = $1 AND p.tags[1] = $2",
+			wantArgs:  []interface{}{1, "go"},
+		},
+		{
+			name: "array index out of range",
+			fields: models.Fields{
+				{Name: "tags", ArrayIndex: index(0), Value: "go"},
+			},
+			wantQuery: models.ErrInvalidArrayIndex.Error(),
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tableTest {
+		gotQuery, gotArgs := BuildSQLWhere(tt.fields)
+		assert.Equal(t, tt.wantQuery, gotQuery, tt.name)
+		assert.Equal(t, tt.wantArgs, gotArgs, tt.name)
+	}
+}
+
+func TestBuildSQLWhere_InAnyThreshold(t *testing.T) {
+	ids := func(n int) []int {
+		items := make([]int, n)
+		for k := range items {
+			items[k] = k + 1
+		}
+		return items
+	}
+	inline := func(n int) string {
+		items := make([]string, n)
+		for k, id := range ids(n) {
+			items[k] = fmt.Sprint(id)
+		}
+		return strings.Join(items, ",")
+	}
+
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name: "IN below the threshold",
+			fields: models.Fields{
+				{Name: "is_active", Value: true},
+				{Name: "id", Value: ids(99), Operator: models.In},
+			},
+			wantQuery: fmt.Sprintf("WHERE is_active = $1 AND id IN (%s)", inline(99)),
+			wantArgs:  []interface{}{true},
+		},
+		{
+			name: "IN at the threshold",
+			fields: models.Fields{
+				{Name: "id", Value: ids(100), Operator: models.In},
+			},
+			wantQuery: fmt.Sprintf("WHERE id IN (%s)", inline(100)),
+			wantArgs:  nil,
+		},
+		{
+			name: "IN above the threshold",
+			fields: models.Fields{
+				{Name: "is_active", Value: true},
+				{Name: "id", Value: ids(101), Operator: models.In},
+				{Name: "code", Value: "COL"},
+			},
+			wantQuery: "WHERE is_active = $1 AND id = ANY($2) AND code = $3",
+			wantArgs:  []interface{}{true, pq.Array(ids(101)), "COL"},
+		},
+		{
+			name: "NOT IN above the threshold",
+			fields: models.Fields{
+				{Name: "id", Value: ids(101), Operator: models.NotIn},
+			},
+			wantQuery: "WHERE id <> ALL($1)",
+			wantArgs:  []interface{}{pq.Array(ids(101))},
+		},
+		{
+			name: "IN above the threshold ignoring case",
+			fields: models.Fields{
+				{Name: "Code", Value: append(make([]string, 100), "COL"), Operator: models.In, IgnoreCase: true},
+			},
+			wantQuery: "WHERE lower(code) = ANY($1)",
+			wantArgs:  []interface{}{pq.Array(append(make([]string, 100), "col"))},
+		},
+	}
+
+	for _, tt := range tableTest {
+		gotQuery, gotArgs := BuildSQLWhere(tt.fields)
+		assert.Equal(t, tt.wantQuery, gotQuery, tt.name)
+		assert.Equal(t, tt.wantArgs, gotArgs, tt.name)
+	}
+}
+
+func TestBuildSQLWhere_InlineStringIN(t *testing.T) {
+	fields := models.Fields{
+		{Name: "id", Value: []int{1, 2}, Operator: models.In},
+		{Name: "Code", Value: []string{"COL", "Cop"}, Operator: models.In, IgnoreCase: true},
+		{Name: "is_active", Value: true},
+	}
+
+	gotQuery, gotArgs := BuildSQLWhere(fields)
+	assert.Equal(t, "WHERE id IN (1,2) AND lower(code) IN ($1, $2) AND is_active = $3", gotQuery)
+	assert.Equal(t, []interface{}{"col", "cop", true}, gotArgs)
+
+	InlineStringIN = true
+	defer func() { InlineStringIN = false }()
+
+	gotQuery, gotArgs = BuildSQLWhere(fields)
+	assert.Equal(t, "WHERE id IN (1,2) AND lower(code) IN ('col','cop') AND is_active = $1", gotQuery)
+	assert.Equal(t, []interface{}{true}, gotArgs)
+}
+
+func TestBuildSQLWhere_ParameterizeIN(t *testing.T) {
+	fields := models.Fields{
+		{Name: "employer_id", Value: 1},
+		{Name: "id", Value: []int64{10, 20}, Operator: models.In},
+		{Name: "Code", Value: []string{"COL", "Cop"}, Operator: models.NotIn, IgnoreCase: true},
+		{Name: "contract_id", Value: []uint{}, Operator: models.In},
+		{Name: "is_active", Value: true},
+	}
+
+	ParameterizeIN = true
+	defer func() { ParameterizeIN = false }()
+
+	gotQuery, gotArgs := BuildSQLWhere(fields)
+	assert.Equal(t, "WHERE employer_id = $1 AND id IN ($2, $3) AND lower(code) NOT IN ($4, $5) AND contract_id = 0 AND is_active = $6", gotQuery)
+	assert.Equal(t, []interface{}{1, int64(10), int64(20), "col", "cop", true}, gotArgs)
+}
+
+func TestBuildSQLWhere_NullSafeNotIn(t *testing.T) {
+	ParameterizeIN = true
+	defer func() { ParameterizeIN = false }()
+
+	tests := []struct {
+		name      string
+		field     models.Field
+		wantNaive string
+		wantSafe  string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "values with NULL",
+			field:     models.Field{Name: "manager_id", Operator: models.NotIn, Value: []interface{}{1, nil}},
+			wantNaive: "WHERE employer_id = $1 AND manager_id NOT IN ($2, $3) AND is_active = $4",
+			wantSafe:  "WHERE employer_id = $1 AND NOT EXISTS (SELECT 1 WHERE manager_id IN ($2, $3)) AND is_active = $4",
+			wantArgs:  []interface{}{7, 1, nil, true},
+		},
+		{
+			name:      "subquery",
+			field:     models.Field{Name: "id", Operator: models.NotIn, RawSubquery: "SELECT manager_id FROM teams"},
+			wantNaive: "WHERE employer_id = $1 AND id NOT IN (SELECT manager_id FROM teams) AND is_active = $2",
+			wantSafe:  "WHERE employer_id = $1 AND NOT EXISTS (SELECT 1 WHERE id IN (SELECT manager_id FROM teams)) AND is_active = $2",
+			wantArgs:  []interface{}{7, true},
+		},
+		{
+			name:      "empty values",
+			field:     models.Field{Name: "id", Operator: models.NotIn, Value: []int{}},
+			wantNaive: "WHERE employer_id = $1 AND TRUE AND is_active = $2",
+			wantSafe:  "WHERE employer_id = $1 AND TRUE AND is_active = $2",
+			wantArgs:  []interface{}{7, true},
+		},
+		{
+			name:      "unsupported value",
+			field:     models.Field{Name: "id", Operator: models.NotIn, Value: "fake"},
+			wantNaive: "WHERE employer_id = $1 AND FALSE AND is_active = $2",
+			wantSafe:  "WHERE employer_id = $1 AND FALSE AND is_active = $2",
+			wantArgs:  []interface{}{7, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			safe := tt.field
+			safe.NullSafe = true
+
+			for _, c := range []struct {
+				field models.Field
+				want  string
+			}{{tt.field, tt.wantNaive}, {safe, tt.wantSafe}} {
+				gotQuery, gotArgs := BuildSQLWhere(models.Fields{
+					{Name: "employer_id", Value: 7},
+					c.field,
+					{Name: "is_active", Value: true},
+				})
+				assert.Equal(t, c.want, gotQuery)
+				assert.Equal(t, tt.wantArgs, gotArgs)
+			}
+		})
+	}
+}
+
+func TestBuildSQLWhereReusingArgs(t *testing.T) {
+	largeIN := make([]int, InAnyThreshold+1)
+	for k := range largeIN {
+		largeIN[k] = k + 1
+	}
+
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "where with emtpy fields",
+			fields:    models.Fields{},
+			wantQuery: "",
+			wantArgs:  nil,
+		},
+		{
+			name: "where with a search value repeated in several columns",
+			fields: models.Fields{
+				{Name: "is_active", Value: true},
+				{GroupOpen: true, Name: "name", Value: "%go%", Operator: models.Ilike, ChainingKey: models.Or},
+				{Name: "description", Value: "%go%", Operator: models.Ilike, ChainingKey: models.Or},
+				{GroupClose: true, Name: "tags", Value: "%go%", Operator: models.Ilike},
+			},
+			wantQuery: "WHERE is_active = $1 AND (name ILIKE $2 OR description ILIKE $2 OR tags ILIKE $2)",
+			wantArgs:  []interface{}{true, "%go%"},
+		},
+		{
+			name: "where with same value in different types",
+			fields: models.Fields{
+				{Name: "code", Value: "1"},
+				{Name: "id", Value: 1},
+				{Name: "parent_id", Value: 1},
+				{Name: "begins_at", Operator: models.Between, FromValue: 1, ToValue: 5},
+			},
+			wantQuery: "WHERE code = $1 AND id = $2 AND parent_id = $2 AND begins_at BETWEEN $2 AND $3",
+			wantArgs:  []interface{}{"1", 1, 5},
+		},
+		{
+			name: "where with the same array in ANY",
+			fields: models.Fields{
+				{Name: "a", Value: []int{1, 2}, Operator: models.Any},
+				{Name: "b", Value: []int{1, 2}, Operator: models.Any},
+			},
+			wantQuery: "WHERE a = ANY($1) AND b = ANY($2)",
+			wantArgs:  []interface{}{pq.Array([]int{1, 2}), pq.Array([]int{1, 2})},
+		},
+		{
+			name: "where with the same IN above the threshold",
+			fields: models.Fields{
+				{Name: "a", Value: largeIN, Operator: models.In},
+				{Name: "b", Value: largeIN, Operator: models.In},
+			},
+			wantQuery: "WHERE a = ANY($1) AND b = ANY($2)",
+			wantArgs:  []interface{}{pq.Array(largeIN), pq.Array(largeIN)},
+		},
+	}
+
+	for _, tt := range tableTest {
+		gotQuery, gotArgs := BuildSQLWhereReusingArgs(tt.fields)
+		assert.Equal(t, tt.wantQuery, gotQuery, tt.name)
+		assert.Equal(t, tt.wantArgs, gotArgs, tt.name)
+	}
+}
+
+func TestCheckPlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		args    []interface{}
+		wantErr error
+	}{
+		{
+			name:  "without placeholders and args",
+			query: "WHERE id IN (1,2,3)",
+			args:  nil,
+		},
+		{
+			name:  "placeholders match args",
+			query: "WHERE name = $1 AND begins_at BETWEEN $2 AND $3 AND code IN ('$9')",
+			args:  []interface{}{"Alejandro", 1, 5},
+		},
+		{
+			name:  "reused placeholders match args",
+			query: "WHERE name ILIKE $1 OR description ILIKE $1",
+			args:  []interface{}{"%go%"},
+		},
+		{
+			name:    "more placeholders than args",
+			query:   "WHERE name = $1 AND begins_at BETWEEN $2 AND $3",
+			args:    []interface{}{"Alejandro", 1},
+			wantErr: models.ErrPlaceholdersAndArgsMissMatch,
+		},
+		{
+			name:    "more args than placeholders",
+			query:   "WHERE name = $1 AND ends_at = pp.ends_at",
+			args:    []interface{}{"Alejandro", 1},
+			wantErr: models.ErrPlaceholdersAndArgsMissMatch,
+		},
+		{
+			name:    "placeholders are not contiguous",
+			query:   "WHERE name = $1 AND age = $3",
+			args:    []interface{}{"Alejandro", 30},
+			wantErr: models.ErrPlaceholdersAndArgsMissMatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, checkPlaceholders(tt.query, tt.args, 1), tt.wantErr)
+		})
+	}
+}
+
+func TestColumnsAliased(t *testing.T) {
+	tableTest := []struct {
+		aliased string
+		fields  []string
+		want    string
+	}{
+		{
+			aliased: "b",
+			fields:  []string{"title", "slug", "content", "poster"},
+			want:    "b.id, b.title, b.slug, b.content, b.poster, b.created_at, b.updated_at",
+		},
+		{
+			aliased: "nothing",
+			fields:  []string{},
+			want:    "",
+		},
+		{
+			aliased: "one",
+			fields:  []string{"one_field"},
+			want:    "one.id, one.one_field, one.created_at, one.updated_at",
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, ColumnsAliased(tt.fields, tt.aliased))
+	}
+}
+
+func TestBuildSQLOrderBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		sorts models.SortFields
+		want  string
+	}{
+		{
+			name:  "Without sort order specification",
+			sorts: models.SortFields{{Name: "id"}, {Name: "begins_at"}},
+			want:  "ORDER BY id ASC, begins_at ASC",
+		},
+		{
+			name:  "With sort order specification",
+			sorts: models.SortFields{{Name: "id", Order: models.Desc}, {Name: "begins_at", Order: models.Asc}},
+			want:  "ORDER BY id DESC, begins_at ASC",
+		},
+		{
+			name:  "With sort alias",
+			sorts: models.SortFields{{Name: "id", Source: "a"}, {Name: "begins_at", Source: "b"}},
+			want:  "ORDER BY a.id ASC, b.begins_at ASC",
+		},
+		{
+			name:  "One field sort",
+			sorts: models.SortFields{{Name: "id"}},
+			want:  "ORDER BY id ASC",
+		},
+		{
+			name:  "With nulls order",
+			sorts: models.SortFields{{Name: "ends_at", Nulls: models.NullsFirst}, {Name: "id"}},
+			want:  "ORDER BY ends_at ASC NULLS FIRST, id ASC",
+		},
+		{
+			name:  "With raw expression",
+			sorts: models.SortFields{{RawExpression: "lower(Name)", Order: models.Desc}},
+			want:  "ORDER BY lower(Name) DESC",
+		},
+		{
+			name: "With raw expression and a column",
+			sorts: models.SortFields{
+				{RawExpression: "CASE WHEN status = 'open' THEN 0 ELSE 1 END", Source: "t"},
+				{Name: "Created_At", Source: "t", Order: models.Desc},
+			},
+			want: "ORDER BY CASE WHEN status = 'open' THEN 0 ELSE 1 END ASC, t.created_at DESC",
+		},
+		{
+			name: "With expression, order and nulls order",
+			sorts: models.SortFields{
+				{Name: "priority", RawExpression: "COALESCE(priority, 0)", Order: models.Desc, Nulls: models.NullsLast},
+				{Name: "id", Source: "t"},
+			},
+			want: "ORDER BY COALESCE(priority, 0) DESC NULLS LAST, t.id ASC",
+		},
+		{
+			name:  "Without field sorts",
+			sorts: models.SortFields{},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildSQLOrderBy(tt.sorts)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildSQLCount(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "count without filters",
+			fields:    models.Fields{},
+			wantQuery: "SELECT COUNT(*) FROM contracts",
+			wantArgs:  nil,
+		},
+		{
+			name: "count with a filter",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+			},
+			wantQuery: "SELECT COUNT(*) FROM contracts WHERE employer_id = $1",
+			wantArgs:  []interface{}{1},
+		},
+		{
+			name: "count with a group condition",
+			fields: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "status", Value: "active", ChainingKey: models.Or, GroupOpen: true},
+				{Name: "status", Value: "pending", GroupClose: true},
+			},
+			wantQuery: "SELECT COUNT(*) FROM contracts WHERE employer_id = $1 AND (status = $2 OR status = $3)",
+			wantArgs:  []interface{}{1, "active", "pending"},
+		},
+		{
+			name: "count with an invalid filter",
+			fields: models.Fields{
+				{Name: "begins_at", Operator: models.Between, ToValue: 2},
+			},
+			wantQuery: models.ErrFromValueIsEmpty.Error(),
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tableTest {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLCount("contracts", tt.fields)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLFacetCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		table       string
+		facetColumn string
+		baseFilters models.Fields
+		wantQuery   string
+		wantArgs    []interface{}
+	}{
+		{
+			name:        "facet with base filters",
+			table:       "products",
+			facetColumn: "Brand",
+			baseFilters: models.Fields{
+				{Name: "category_id", Value: 3},
+				{Name: "name", Value: "%phone%", Operator: models.Ilike},
+			},
+			wantQuery: "SELECT brand, COUNT(*) FROM products WHERE category_id = $1 AND name ILIKE $2 GROUP BY brand",
+			wantArgs:  []interface{}{3, "%phone%"},
+		},
+		{
+			name:        "facet without base filters",
+			table:       "products",
+			facetColumn: "brand",
+			baseFilters: models.Fields{},
+			wantQuery:   "SELECT brand, COUNT(*) FROM products GROUP BY brand",
+			wantArgs:    nil,
+		},
+		{
+			name:        "facet with invalid base filters",
+			table:       "products",
+			facetColumn: "brand",
+			baseFilters: models.Fields{
+				{Name: "price", Operator: models.Between, FromValue: 100, ToValue: 10},
+			},
+			wantQuery: models.ErrFromIsGreaterThanTo.Error(),
+			wantArgs:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLFacetCount(tt.table, tt.facetColumn, tt.baseFilters)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLExists(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		fields    models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "exists without conditions",
+			fields:    models.Fields{},
+			wantQuery: "SELECT EXISTS(SELECT 1 FROM users)",
+			wantArgs:  nil,
+		},
+		{
+			name: "exists with conditions",
+			fields: models.Fields{
+				{Name: "Email", Value: "ana@example.com"},
+				{Name: "deleted_at", Operator: models.IsNull},
+			},
+			wantQuery: "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND deleted_at IS NULL)",
+			wantArgs:  []interface{}{"ana@example.com"},
+		},
+	}
+
+	for _, tt := range tableTest {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLExists("users", tt.fields)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLCountByStatus(t *testing.T) {
+	tableTest := []struct {
+		name        string
+		statuses    []string
+		baseFilters models.Fields
+		wantQuery   string
+		wantArgs    []interface{}
+	}{
+		{
+			name:     "two statuses with base filters",
+			statuses: []string{"open", "closed"},
+			baseFilters: models.Fields{
+				{Name: "employer_id", Value: 1},
+				{Name: "created_at", Operator: models.GreaterThanOrEqualTo, Value: "2021-01-01"},
+			},
+			wantQuery: "SELECT count(*) FILTER (WHERE status = $1) AS open, count(*) FILTER (WHERE status = $2) AS closed FROM tickets WHERE employer_id = $3 AND created_at >= $4",
+			wantArgs:  []interface{}{"open", "closed", 1, "2021-01-01"},
+		},
+		{
+			name:      "without base filters",
+			statuses:  []string{"open"},
+			wantQuery: "SELECT count(*) FILTER (WHERE status = $1) AS open FROM tickets",
+			wantArgs:  []interface{}{"open"},
+		},
+		{
+			name:      "status that is not a valid column name",
+			statuses:  []string{"open", "in progress"},
+			wantQuery: ErrInvalidIdentifier,
+			wantArgs:  nil,
+		},
+		{
+			name:      "empty statuses",
+			statuses:  nil,
+			wantQuery: ErrFieldsAreEmpty,
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tableTest {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLCountByStatus("tickets", "Status", tt.statuses, tt.baseFilters)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLJoins(t *testing.T) {
+	tests := []struct {
+		name  string
+		joins models.Joins
+		want  string
+	}{
+		{
+			name:  "without joins",
+			joins: models.Joins{},
+			want:  "",
+		},
+		{
+			name:  "join without type",
+			joins: models.Joins{{Table: "contracts", Alias: "c", OnLeft: "c.employer_id", OnRight: "e.id"}},
+			want:  "INNER JOIN contracts c ON c.employer_id = e.id",
+		},
+		{
+			name:  "single left join",
+			joins: models.Joins{{Type: models.LeftJoin, Table: "employers", Alias: "e", OnLeft: "e.id", OnRight: "c.employer_id"}},
+			want:  "LEFT JOIN employers e ON e.id = c.employer_id",
+		},
+		{
+			name: "chain of two joins with aliases",
+			joins: models.Joins{
+				{Type: models.InnerJoin, Table: "employers", Alias: "e", OnLeft: "e.id", OnRight: "c.employer_id"},
+				{Type: models.RightJoin, Table: "periods", Alias: "p", OnLeft: "p.employer_id", OnRight: "e.id"},
+			},
+			want: "INNER JOIN employers e ON e.id = c.employer_id RIGHT JOIN periods p ON p.employer_id = e.id",
+		},
+		{
+			name:  "full join",
+			joins: models.Joins{{Type: models.FullJoin, Table: "periods", Alias: "p", OnLeft: "p.id", OnRight: "c.period_id"}},
+			want:  "FULL JOIN periods p ON p.id = c.period_id",
+		},
+		{
+			name:  "null-safe join",
+			joins: models.Joins{{Type: models.LeftJoin, Table: "periods", Alias: "p", OnLeft: "p.ends_at", OnRight: "c.ends_at", Operator: models.IsNotDistinctFrom}},
+			want:  "LEFT JOIN periods p ON p.ends_at IS NOT DISTINCT FROM c.ends_at",
+		},
+		{
+			name: "lateral left join",
+			joins: models.Joins{
+				{Type: models.LeftJoin, Lateral: true, Table: "SELECT p.amount FROM payments p WHERE p.user_id = u.id ORDER BY p.created_at DESC LIMIT 1", Alias: "lp"},
+			},
+			want: "LEFT JOIN LATERAL (SELECT p.amount FROM payments p WHERE p.user_id = u.id ORDER BY p.created_at DESC LIMIT 1) lp ON true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BuildSQLJoins(tt.joins))
+		})
+	}
+}
+
+func TestBuildSQLWindowColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		column  models.WindowColumn
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "running sum with an explicit frame",
+			column: models.WindowColumn{Function: "SUM(amount)", OrderBy: models.SortFields{{Name: "ts"}}, Frame: models.WindowFrame{Mode: models.Rows, Start: models.UnboundedPreceding, End: models.CurrentRow}, Alias: "running_total"},
+			want:   "SUM(amount) OVER (ORDER BY ts ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS running_total",
+		},
+		{
+			name:   "partitioned sum with a frame without end",
+			column: models.WindowColumn{Function: "SUM(amount)", PartitionBy: []string{"User_ID"}, OrderBy: models.SortFields{{Name: "ts"}}, Frame: models.WindowFrame{Mode: models.Range, Start: models.UnboundedPreceding}, Alias: "total"},
+			want:   "SUM(amount) OVER (PARTITION BY user_id ORDER BY ts ASC RANGE UNBOUNDED PRECEDING) AS total",
+		},
+		{
+			name:   "without window specification",
+			column: models.WindowColumn{Function: "COUNT(*)", Alias: "total_count"},
+			want:   "COUNT(*) OVER () AS total_count",
+		},
+		{
+			name:   "rank by category",
+			column: models.NewRankColumn(models.Rank, []string{"category"}, models.SortFields{{Name: "score", Order: models.Desc}}, "rank"),
+			want:   "RANK() OVER (PARTITION BY category ORDER BY score DESC) AS rank",
+		},
+		{
+			name:   "dense rank with aliases",
+			column: models.NewRankColumn(models.DenseRank, []string{"p.category"}, models.SortFields{{Source: "p", Name: "score", Order: models.Desc}}, "position"),
+			want:   "DENSE_RANK() OVER (PARTITION BY p.category ORDER BY p.score DESC) AS position",
+		},
+		{
+			name:   "row number without partition",
+			column: models.NewRankColumn(models.RowNumber, nil, models.SortFields{{Name: "created_at"}, {Name: "id"}}, "row_number"),
+			want:   "ROW_NUMBER() OVER (ORDER BY created_at ASC, id ASC) AS row_number",
+		},
+		{
+			name:    "frame with a token not allowed",
+			column:  models.WindowColumn{Function: "SUM(amount)", Frame: models.WindowFrame{Mode: models.Rows, Start: "1; DROP TABLE users"}, Alias: "total"},
+			wantErr: models.ErrInvalidWindowFrame,
+		},
+		{
+			name:    "frame with a mode not allowed",
+			column:  models.WindowColumn{Function: "SUM(amount)", Frame: models.WindowFrame{Mode: "LINES", Start: models.CurrentRow}, Alias: "total"},
+			wantErr: models.ErrInvalidWindowFrame,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildSQLWindowColumn(tt.column)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildSQLGroupBy(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []string
+		want   string
+	}{
+		{
+			name:   "without columns",
+			fields: []string{},
+			want:   "",
+		},
+		{
+			name:   "one column",
+			fields: []string{"Status"},
+			want:   "GROUP BY status",
+		},
+		{
+			name:   "several columns with an aliased one",
+			fields: []string{"c.Employer_ID", "status", "country"},
+			want:   "GROUP BY c.employer_id, status, country",
+		},
+		{
+			name:   "invalid column",
+			fields: []string{"status; DROP TABLE contracts"},
+			want:   ErrInvalidIdentifier,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BuildSQLGroupBy(tt.fields))
+		})
+	}
+}
+
+func TestBuildSQLGroupByMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode models.GroupingMode
+		sets [][]string
+		want string
+	}{
+		{
+			name: "rollup",
+			mode: models.Rollup,
+			sets: [][]string{{"Country"}, {"city"}},
+			want: "GROUP BY ROLLUP (country, city)",
+		},
+		{
+			name: "cube with a composite element",
+			mode: models.Cube,
+			sets: [][]string{{"s.brand"}, {"s.size", "s.color"}},
+			want: "GROUP BY CUBE (s.brand, (s.size, s.color))",
+		},
+		{
+			name: "grouping sets with grand total",
+			mode: models.GroupingSets,
+			sets: [][]string{{"brand", "size"}, {"brand"}, {}},
+			want: "GROUP BY GROUPING SETS ((brand, size), (brand), ())",
+		},
+		{
+			name: "rollup with an empty set",
+			mode: models.Rollup,
+			sets: [][]string{{"brand"}, {}},
+			want: ErrFieldsAreEmpty,
+		},
+		{
+			name: "without sets",
+			mode: models.Rollup,
+			sets: [][]string{},
+			want: ErrFieldsAreEmpty,
+		},
+		{
+			name: "invalid column",
+			mode: models.Cube,
+			sets: [][]string{{"brand); DROP TABLE users; --"}},
+			want: ErrInvalidIdentifier,
+		},
+		{
+			name: "invalid mode",
+			mode: "SETS",
+			sets: [][]string{{"brand"}},
+			want: ErrInvalidGroupingMode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BuildSQLGroupByMode(tt.mode, tt.sets))
+		})
+	}
+}
+
+func TestBuildSQLAggregateColumn(t *testing.T) {
+	tests := []struct {
+		name   string
+		column models.AggregateColumn
+		want   string
+	}{
+		{
+			name:   "array_agg with order",
+			column: models.AggregateColumn{Function: models.ArrayAgg, Name: "Tag", OrderBy: models.SortFields{{Name: "tag"}}, Alias: "tags"},
+			want:   "array_agg(tag ORDER BY tag ASC) AS tags",
+		},
+		{
+			name:   "array_agg without order",
+			column: models.AggregateColumn{Function: models.ArrayAgg, Source: "t", Name: "id", Alias: "ids"},
+			want:   "array_agg(t.id) AS ids",
+		},
+		{
+			name:   "string_agg with separator and order",
+			column: models.AggregateColumn{Function: models.StringAgg, Name: "name", Separator: ", ", OrderBy: models.SortFields{{Name: "name", Order: models.Desc}}, Alias: "names"},
+			want:   "string_agg(name, ', ' ORDER BY name DESC) AS names",
+		},
+		{
+			name:   "string_agg with quoted separator",
+			column: models.AggregateColumn{Function: models.StringAgg, Name: "name", Separator: "'", Alias: "names"},
+			want:   "string_agg(name, '''') AS names",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BuildSQLAggregateColumn(tt.column))
+		})
+	}
+}
+
+func TestBuildIN(t *testing.T) {
+	tableTest := []struct {
+		field     models.Field
+		wantQuery string
+	}{
+		{
+			field: models.Field{
+				Name: "id", Value: []uint{1, 2, 3}, Operator: models.In,
+			},
+			wantQuery: "id IN (1,2,3)",
+		},
+		{
+			field: models.Field{
+				Name: "employee_id", Value: []int{5, 6, 7}, Operator: models.In,
+			},
+			wantQuery: "employee_id IN (5,6,7)",
+		},
+		{
+			field: models.Field{
+				Name: "account_id", Value: []int64{10, 20}, Operator: models.In,
+			},
+			wantQuery: "account_id IN (10,20)",
+		},
+		{
+			field: models.Field{
+				Name: "rate", Value: []float64{1.5, 2.5, 3}, Operator: models.In,
+			},
+			wantQuery: "rate IN (1.5,2.5,3)",
+		},
+		{
+			field: models.Field{
+				Name: "is_active", Value: []bool{true, false}, Operator: models.In,
+			},
+			wantQuery: "is_active IN (TRUE,FALSE)",
+		},
+		{
+			field: models.Field{
+				Name: "account_id", Value: []int64{}, Operator: models.In,
+			},
+			wantQuery: "account_id = 0",
+		},
+		{
+			field: models.Field{
+				Name: "marital_status", Value: []string{"SINGLE"}, Operator: models.In,
+			},
+			wantQuery: "marital_status IN ('SINGLE')",
+		},
+		{
+			field: models.Field{
+				Name: "Code", Value: []string{"COL", "Cop"}, Operator: models.In, IgnoreCase: true,
+			},
+			wantQuery: "lower(code) IN ('col','cop')",
+		},
+		{
+			field: models.Field{
+				Name: "last_name", Value: []string{"O'Brien", "D'Arcy'); DROP TABLE users; --"}, Operator: models.In,
+			},
+			wantQuery: "last_name IN ('O''Brien','D''Arcy''); DROP TABLE users; --')",
+		},
+		{
+			field: models.Field{
+				Name: "path", Value: []string{`C:\temp`}, Operator: models.In,
+			},
+			wantQuery: `path IN ('C:\temp')`,
+		},
+		{
+			field: models.Field{
+				Name: "employee_id", Value: []int{5, 6}, Operator: models.In, IgnoreCase: true,
+			},
+			wantQuery: "employee_id IN (5,6)",
+		},
+		{
+			field: models.Field{
+				Name: "employee_id", Value: "fake", Operator: models.In,
 			},
-			wantQuery: "WHERE c.employer_id = $1 AND c.ends_at = pp.ends_at AND c.termination_date IS NOT NULL AND c.pay_frequency_id = $2 AND (cs.description ILIKE $3 OR c.frequency >= s.months AND c.begins_at BETWEEN $4 AND $5 AND (cs.description ILIKE $6 AND c.hire_date <= $7))",
-			wantArgs:  []interface{}{1, 2, "ACTIVE", parseToDate(2020, 1, 1), parseToDate(2021, 12, 31), "CREATED", "2021-04-28"},
+			wantQuery: "employee_id = 0",
+		},
+		{
+			field: models.Field{
+				Name: "contract_id", Value: []uint{}, Operator: models.In,
+			},
+			wantQuery: "contract_id = 0",
 		},
 	}
 
 	for _, tt := range tableTest {
-		gotQuery, gotArgs := BuildSQLWhere(tt.fields)
-		assert.Equal(t, tt.wantQuery, gotQuery, tt.name)
-		assert.Equal(t, tt.wantArgs, gotArgs, tt.name)
+		gotQuery := BuildIN(tt.field)
+		assert.Equal(t, tt.wantQuery, gotQuery)
 	}
 }
 
-func TestColumnsAliased(t *testing.T) {
+func TestBuildIN_NotIn(t *testing.T) {
 	tableTest := []struct {
-		aliased string
-		fields  []string
-		want    string
+		field     models.Field
+		wantQuery string
 	}{
 		{
-			aliased: "b",
-			fields:  []string{"title", "slug", "content", "poster"},
-			want:    "b.id, b.title, b.slug, b.content, b.poster, b.created_at, b.updated_at",
+			field: models.Field{
+				Name: "id", Value: []uint{1, 2, 3}, Operator: models.NotIn,
+			},
+			wantQuery: "id NOT IN (1,2,3)",
 		},
 		{
-			aliased: "nothing",
-			fields:  []string{},
-			want:    "",
+			field: models.Field{
+				Name: "employee_id", Value: []int{5, 6, 7}, Operator: models.NotIn,
+			},
+			wantQuery: "employee_id NOT IN (5,6,7)",
 		},
 		{
-			aliased: "one",
-			fields:  []string{"one_field"},
-			want:    "one.id, one.one_field, one.created_at, one.updated_at",
+			field: models.Field{
+				Name: "marital_status", Value: []string{"SINGLE"}, Operator: models.NotIn,
+			},
+			wantQuery: "marital_status NOT IN ('SINGLE')",
+		},
+		{
+			field: models.Field{
+				Name: "Code", Value: []string{"COL", "Cop"}, Operator: models.NotIn, IgnoreCase: true,
+			},
+			wantQuery: "lower(code) NOT IN ('col','cop')",
+		},
+		{
+			field: models.Field{
+				Name: "employee_id", Value: "fake", Operator: models.NotIn,
+			},
+			wantQuery: "FALSE",
+		},
+		{
+			field: models.Field{
+				Name: "employee_id", Value: []int32{1, 2}, Operator: models.NotIn,
+			},
+			wantQuery: "FALSE",
+		},
+		{
+			field: models.Field{
+				Name: "contract_id", Value: []uint{}, Operator: models.NotIn,
+			},
+			wantQuery: "TRUE",
 		},
 	}
 
 	for _, tt := range tableTest {
-		assert.Equal(t, tt.want, ColumnsAliased(tt.fields, tt.aliased))
+		gotQuery := BuildIN(tt.field)
+		assert.Equal(t, tt.wantQuery, gotQuery)
 	}
 }
 
-func TestBuildSQLOrderBy(t *testing.T) {
+func parseToDate(year, month, day int) time.Time {
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBuildSQLLock(t *testing.T) {
 	tests := []struct {
-		name  string
-		sorts models.SortFields
-		want  string
+		name string
+		lock models.Lock
+		want string
 	}{
 		{
-			name:  "Without sort order specification",
-			sorts: models.SortFields{{Name: "id"}, {Name: "begins_at"}},
-			want:  "ORDER BY id ASC, begins_at ASC",
+			name: "without lock",
+			lock: models.Lock{},
+			want: "",
 		},
 		{
-			name:  "With sort order specification",
-			sorts: models.SortFields{{Name: "id", Order: models.Desc}, {Name: "begins_at", Order: models.Asc}},
-			want:  "ORDER BY id DESC, begins_at ASC",
+			name: "lock of all the tables",
+			lock: models.Lock{Mode: models.ForUpdate},
+			want: "FOR UPDATE",
 		},
 		{
-			name:  "With sort alias",
-			sorts: models.SortFields{{Name: "id", Source: "a"}, {Name: "begins_at", Source: "b"}},
-			want:  "ORDER BY a.id ASC, b.begins_at ASC",
+			name: "lock of one table",
+			lock: models.Lock{Mode: models.ForUpdate, OfTables: []string{"contracts"}},
+			want: "FOR UPDATE OF contracts",
 		},
 		{
-			name:  "One field sort",
-			sorts: models.SortFields{{Name: "id"}},
-			want:  "ORDER BY id ASC",
+			name: "lock of two tables",
+			lock: models.Lock{Mode: models.ForNoKeyUpdate, OfTables: []string{"contracts", "periods"}},
+			want: "FOR NO KEY UPDATE OF contracts, periods",
 		},
 		{
-			name:  "Without field sorts",
-			sorts: models.SortFields{},
-			want:  "",
+			name: "invalid table",
+			lock: models.Lock{Mode: models.ForUpdate, OfTables: []string{"contracts NOWAIT"}},
+			want: ErrInvalidIdentifier,
+		},
+		{
+			name: "invalid mode",
+			lock: models.Lock{Mode: "FOR DELETE"},
+			want: ErrInvalidLockMode,
 		},
 	}
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := BuildSQLOrderBy(tt.sorts)
-			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, BuildSQLLock(tt.lock))
 		})
 	}
 }
 
-func TestBuildIN(t *testing.T) {
-	tableTest := []struct {
-		field     models.Field
-		wantQuery string
+func TestBuildSQLPagination(t *testing.T) {
+	tests := []struct {
+		name string
+		args models.Pagination
+		want string
 	}{
 		{
-			field: models.Field{
-				Name: "id", Value: []uint{1, 2, 3}, Operator: models.In,
+			name: "empty pagination",
+			args: models.Pagination{},
+			want: "",
+		},
+		{
+			name: "first page",
+			args: models.Pagination{
+				Page:     0,
+				Limit:    5,
+				MaxLimit: 0,
 			},
-			wantQuery: "id IN (1,2,3)",
+			want: "LIMIT 5 OFFSET 0",
 		},
 		{
-			field: models.Field{
-				Name: "employee_id", Value: []int{5, 6, 7}, Operator: models.In,
+			name: "page 2",
+			args: models.Pagination{
+				Page:     2,
+				Limit:    10,
+				MaxLimit: 10,
 			},
-			wantQuery: "employee_id IN (5,6,7)",
+			want: "LIMIT 10 OFFSET 10",
 		},
 		{
-			field: models.Field{
-				Name: "marital_status", Value: []string{"SINGLE"}, Operator: models.In,
+			name: "explicit offset",
+			args: models.Pagination{
+				Limit:  5,
+				Offset: 7,
 			},
-			wantQuery: "marital_status IN ('SINGLE')",
+			want: "LIMIT 5 OFFSET 7",
 		},
 		{
-			field: models.Field{
-				Name: "employee_id", Value: "fake", Operator: models.In,
+			name: "explicit offset over the page",
+			args: models.Pagination{
+				Page:   3,
+				Limit:  5,
+				Offset: 7,
 			},
-			wantQuery: "employee_id = 0",
+			want: "LIMIT 5 OFFSET 7",
 		},
 		{
-			field: models.Field{
-				Name: "contract_id", Value: []uint{}, Operator: models.In,
+			name: "explicit offset without limit",
+			args: models.Pagination{
+				Offset: 7,
 			},
-			wantQuery: "contract_id = 0",
+			want: "LIMIT 20 OFFSET 7",
 		},
 	}
-
-	for _, tt := range tableTest {
-		gotQuery := BuildIN(tt.field)
-		assert.Equal(t, tt.wantQuery, gotQuery)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, BuildSQLPagination(tt.args), "BuildSQLPagination(%v)", tt.args)
+		})
 	}
 }
 
-func parseToDate(year, month, day int) time.Time {
-	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+func TestBuildSQLPagination_DefaultMaxLimit(t *testing.T) {
+	DefaultMaxLimit = 50
+	defer func() { DefaultMaxLimit = 20 }()
+
+	assert.Equal(t, "LIMIT 50 OFFSET 50", BuildSQLPagination(models.Pagination{Page: 2}))
+	assert.Equal(t, "LIMIT 50 OFFSET 0", BuildSQLPagination(models.Pagination{Page: 1, Limit: 100}))
+	assert.Equal(t, "LIMIT 30 OFFSET 0", BuildSQLPagination(models.Pagination{Page: 1, Limit: 30}))
+	assert.Equal(t, "LIMIT 10 OFFSET 0", BuildSQLPagination(models.Pagination{Page: 1, Limit: 100, MaxLimit: 10}))
 }
 
-func TestBuildSQLPagination(t *testing.T) {
+func TestBuildSQLPagination_StrictPagination(t *testing.T) {
+	StrictPagination = true
+	defer func() { StrictPagination = false }()
+
+	assert.Equal(t, "LIMIT 10 OFFSET 10", BuildSQLPagination(models.Pagination{Page: 2, Limit: 10, MaxLimit: 50}))
+	assert.Equal(t, ErrInvalidPagination, BuildSQLPagination(models.Pagination{Page: 1, Limit: 100, MaxLimit: 50}))
+	assert.Equal(t, "", BuildSQLPagination(models.Pagination{}))
+	assert.Equal(t, ErrInvalidPagination, BuildSQLPagination(models.Pagination{Page: 1 << 62}))
+	assert.Equal(t, ErrInvalidPagination, BuildSQLPagination(models.Pagination{Page: 1 << 62, MaxLimit: 50}))
+
+	assert.Equal(t, "OFFSET 10 ROWS FETCH FIRST 10 ROWS ONLY", BuildSQLFetch(models.Pagination{Page: 2, Limit: 10, MaxLimit: 50}))
+	assert.Equal(t, ErrInvalidPagination, BuildSQLFetch(models.Pagination{Page: 1, Limit: 100, MaxLimit: 50}))
+	assert.Equal(t, ErrInvalidPagination, BuildSQLFetch(models.Pagination{Page: 1 << 62}))
+
+	invalid := models.Pagination{Page: 1, Limit: 100, MaxLimit: 50}
+
+	query, args := new(QueryBuilder).Select("users", []string{"id"}).Paginate(invalid).Build()
+	assert.Equal(t, ErrInvalidPagination, query)
+	assert.Nil(t, args)
+
+	query, args = BuildSQLAggregateQuery("users", []models.Column{{Name: "role"}}, models.Fields{{Name: "is_active", Value: true}}, []string{"role"}, nil, nil, invalid)
+	assert.Equal(t, ErrInvalidPagination, query)
+	assert.Nil(t, args)
+}
+
+func TestBuildSQLFetch(t *testing.T) {
 	tests := []struct {
 		name string
 		args models.Pagination
@@ -467,7 +2699,7 @@ func TestBuildSQLPagination(t *testing.T) {
 				Limit:    5,
 				MaxLimit: 0,
 			},
-			want: "LIMIT 5 OFFSET 0",
+			want: "OFFSET 0 ROWS FETCH FIRST 5 ROWS ONLY",
 		},
 		{
 			name: "page 2",
@@ -476,12 +2708,12 @@ func TestBuildSQLPagination(t *testing.T) {
 				Limit:    10,
 				MaxLimit: 10,
 			},
-			want: "LIMIT 10 OFFSET 10",
+			want: "OFFSET 10 ROWS FETCH FIRST 10 ROWS ONLY",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, BuildSQLPagination(tt.args), "BuildSQLPagination(%v)", tt.args)
+			assert.Equalf(t, tt.want, BuildSQLFetch(tt.args), "BuildSQLFetch(%v)", tt.args)
 		})
 	}
 }
@@ -520,6 +2752,207 @@ func TestBuildQueryArgsAndPagination(t *testing.T) {
 	}
 }
 
+func TestStripOrderByAndPagination(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "without order by and pagination",
+			query: "SELECT id, name FROM users WHERE id = $1",
+			want:  "SELECT id, name FROM users WHERE id = $1",
+		},
+		{
+			name:  "with order by and pagination",
+			query: "SELECT alpha, beta, gama FROM mytable WHERE id = $1 ORDER BY pipe ASC LIMIT 10 OFFSET 0",
+			want:  "SELECT alpha, beta, gama FROM mytable WHERE id = $1",
+		},
+		{
+			name:  "with only pagination and lower case",
+			query: "select id from users limit 10 offset 20",
+			want:  "select id from users",
+		},
+		{
+			name:  "with fetch pagination",
+			query: "SELECT id FROM users ORDER BY id ASC OFFSET 0 ROWS FETCH FIRST 5 ROWS ONLY",
+			want:  "SELECT id FROM users",
+		},
+		{
+			name:  "with empty where, order by and pagination",
+			query: "SELECT id FROM users   ",
+			want:  "SELECT id FROM users",
+		},
+		{
+			name:  "with subqueries",
+			query: "SELECT id FROM users WHERE id IN (SELECT user_id FROM logs ORDER BY created_at DESC LIMIT 5) ORDER BY id DESC LIMIT 10 OFFSET 10",
+			want:  "SELECT id FROM users WHERE id IN (SELECT user_id FROM logs ORDER BY created_at DESC LIMIT 5)",
+		},
+		{
+			name:  "with keywords into quoted texts and column names",
+			query: "SELECT id, order_by_date, limits FROM users WHERE name = 'ORDER BY LIMIT' ORDER BY id",
+			want:  "SELECT id, order_by_date, limits FROM users WHERE name = 'ORDER BY LIMIT'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripOrderByAndPagination(tt.query))
+		})
+	}
+}
+
+func TestBuildSQLValuesCTE(t *testing.T) {
+	tests := []struct {
+		name      string
+		cte       string
+		columns   []string
+		rows      [][]interface{}
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "two columns and two rows",
+			cte:       "data",
+			columns:   []string{"id", "val"},
+			rows:      [][]interface{}{{1, "a"}, {2, "b"}},
+			wantQuery: "WITH data(id, val) AS (VALUES ($1, $2), ($3, $4))",
+			wantArgs:  []interface{}{1, "a", 2, "b"},
+		},
+		{
+			name:      "empty columns",
+			cte:       "data",
+			columns:   []string{},
+			rows:      [][]interface{}{{1}},
+			wantQuery: ErrFieldsAreEmpty,
+		},
+		{
+			name:      "empty rows",
+			cte:       "data",
+			columns:   []string{"id"},
+			rows:      [][]interface{}{},
+			wantQuery: ErrRowsAreEmpty,
+		},
+		{
+			name:      "row without all the columns",
+			cte:       "data",
+			columns:   []string{"id", "val"},
+			rows:      [][]interface{}{{1, "a"}, {2}},
+			wantQuery: ErrRowsColumnsMissMatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLValuesCTE(tt.cte, tt.columns, tt.rows)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLDeleteBatch(t *testing.T) {
+	tableTest := []struct {
+		name       string
+		conditions models.Fields
+		batchSize  uint
+		wantQuery  string
+		wantArgs   []interface{}
+	}{
+		{
+			name: "batch with conditions",
+			conditions: models.Fields{
+				{Name: "status", Value: "expired"},
+				{Name: "created_at", Operator: models.LessThan, Value: "2021-01-01"},
+			},
+			batchSize: 1000,
+			wantQuery: "DELETE FROM sessions WHERE id IN (SELECT id FROM sessions WHERE status = $1 AND created_at < $2 LIMIT 1000)",
+			wantArgs:  []interface{}{"expired", "2021-01-01"},
+		},
+		{
+			name:       "batch without conditions",
+			conditions: models.Fields{},
+			batchSize:  500,
+			wantQuery:  "DELETE FROM sessions WHERE id IN (SELECT id FROM sessions LIMIT 500)",
+			wantArgs:   nil,
+		},
+		{
+			name:       "empty batch size",
+			conditions: models.Fields{{Name: "status", Value: "expired"}},
+			batchSize:  0,
+			wantQuery:  ErrInvalidRowCount,
+			wantArgs:   nil,
+		},
+	}
+
+	for _, tt := range tableTest {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLDeleteBatch("sessions", tt.conditions, tt.batchSize)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLInsertCTE(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		cteName   string
+		values    []interface{}
+		filters   models.Fields
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "insert and select the inserted row",
+			cteName:   "ins",
+			values:    []interface{}{1, "login"},
+			wantQuery: "WITH ins AS (INSERT INTO logs (user_id, action) VALUES ($1, $2) RETURNING *) SELECT id, user_id FROM ins",
+			wantArgs:  []interface{}{1, "login"},
+		},
+		{
+			name:    "insert and select with filters",
+			cteName: "ins",
+			values:  []interface{}{1, "login"},
+			filters: models.Fields{
+				{Name: "action", Value: "login"},
+			},
+			wantQuery: "WITH ins AS (INSERT INTO logs (user_id, action) VALUES ($1, $2) RETURNING *) SELECT id, user_id FROM ins WHERE action = $3",
+			wantArgs:  []interface{}{1, "login", "login"},
+		},
+		{
+			name:      "values and fields missmatch",
+			cteName:   "ins",
+			values:    []interface{}{1},
+			wantQuery: ErrRowsColumnsMissMatch,
+			wantArgs:  nil,
+		},
+		{
+			name:      "invalid cte name",
+			cteName:   "ins AS (SELECT 1)",
+			values:    []interface{}{1, "login"},
+			wantQuery: ErrInvalidIdentifier,
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tableTest {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLInsertCTE(tt.cteName, "logs", []string{"user_id", "action"}, tt.values, []string{"id", "user_id"}, tt.filters)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestBuildSQLRowToJSON(t *testing.T) {
+	subquery := BuildSQLSelectFields("users", []string{"id", "name"})
+	assert.Equal(t, "SELECT row_to_json(t) FROM (SELECT id, name FROM users) t", BuildSQLRowToJSON(subquery))
+}
+
+func TestBuildSQLJSONAgg(t *testing.T) {
+	subquery := BuildSQLSelectFields("users", []string{"id", "name"})
+	assert.Equal(t, "SELECT json_agg(t) FROM (SELECT id, name FROM users) t", BuildSQLJSONAgg(subquery))
+}
+
 func TestBuildSQLDelete(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -538,3 +2971,22 @@ func TestBuildSQLDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildSQLSoftDeleteByID(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+		want  string
+	}{
+		{
+			name:  "normal soft delete",
+			table: "users",
+			want:  "UPDATE users SET deleted_at = now() WHERE id = $1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, BuildSQLSoftDeleteByID(tt.table), "BuildSQLSoftDeleteByID(%v)", tt.table)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldsFromStruct returns the columns of the `db` tags of the struct that can be written
+// by an INSERT or UPDATE, ej: `db:"name"`. The generated columns, tagged as `db:"full_name,generated"`,
+// are skipped because postgres doesn't allow to write them, and the columns of the Config
+// (id, created_at, updated_at) are skipped because the builders add them
+func FieldsFromStruct(v interface{}) []string {
+	return fieldsFromStruct(v, false)
+}
+
+// SelectFieldsFromStruct returns the columns of the `db` tags of the struct that can be
+// selected, the generated columns included, except the columns of the Config
+func SelectFieldsFromStruct(v interface{}) []string {
+	return fieldsFromStruct(v, true)
+}
+
+func fieldsFromStruct(v interface{}, withGenerated bool) []string {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("db")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		options := strings.Split(tag, ",")
+		name := options[0]
+		if name == "" || name == config.IDColumn || name == config.CreatedAtColumn || name == config.UpdatedAtColumn {
+			continue
+		}
+		if !withGenerated && isGeneratedColumn(options[1:]) {
+			continue
+		}
+
+		fields = append(fields, name)
+	}
+
+	return fields
+}
+
+// isGeneratedColumn returns if the options of the `db` tag mark the column as generated
+func isGeneratedColumn(options []string) bool {
+	for _, option := range options {
+		if strings.TrimSpace(option) == "generated" {
+			return true
+		}
+	}
+
+	return false
+}
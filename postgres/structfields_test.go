@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeUser struct {
+	ID        uint   `db:"id"`
+	FirstName string `db:"first_name"`
+	LastName  string `db:"last_name"`
+	FullName  string `db:"full_name,generated"`
+	Password  string `db:"-"`
+	Token     string
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func TestFieldsFromStruct(t *testing.T) {
+	fields := FieldsFromStruct(fakeUser{})
+
+	assert.Equal(t, []string{"first_name", "last_name"}, fields)
+	assert.Equal(t, "INSERT INTO users (first_name, last_name) VALUES ($1, $2) RETURNING id, created_at", BuildSQLInsert("users", fields))
+	assert.Equal(t, "UPDATE users SET first_name = $1, last_name = $2, updated_at = now() WHERE id = $3", BuildSQLUpdateByID("users", fields))
+	assert.Equal(t, fields, FieldsFromStruct(&fakeUser{}))
+	assert.Nil(t, FieldsFromStruct("users"))
+}
+
+func TestSelectFieldsFromStruct(t *testing.T) {
+	fields := SelectFieldsFromStruct(&fakeUser{})
+
+	assert.Equal(t, []string{"first_name", "last_name", "full_name"}, fields)
+	assert.Equal(t, "SELECT id, first_name, last_name, full_name, created_at, updated_at FROM users", BuildSQLSelect("users", fields))
+}
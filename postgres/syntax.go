@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// trailingCommaRegexp matches a comma followed by `)` or FROM
+var trailingCommaRegexp = regexp.MustCompile(`(?i),\s*(\)|FROM\b)`)
+
+// ValidateSyntax does lightweight checks of a generated query as a development aid:
+// balanced parentheses, balanced quotes, no trailing commas before FROM or `)`,
+// and placeholders that appear in order ($1, $2, ... reusing the previous ones is allowed).
+// It is not a parser, so a valid result doesn't warranty that postgres accepts the query
+func ValidateSyntax(query string) error {
+	unquoted := []byte(query)
+	depth := 0
+	inQuote := false
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if c == '\'' {
+			inQuote = !inQuote
+			continue
+		}
+		if inQuote {
+			unquoted[i] = ' '
+			continue
+		}
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("psql: unbalanced parentheses, unexpected `)` at position %d", i)
+			}
+		}
+	}
+
+	if inQuote {
+		return fmt.Errorf("psql: unbalanced quotes")
+	}
+	if depth != 0 {
+		return fmt.Errorf("psql: unbalanced parentheses, %d not closed", depth)
+	}
+
+	if loc := trailingCommaRegexp.FindIndex(unquoted); loc != nil {
+		return fmt.Errorf("psql: trailing comma at position %d", loc[0])
+	}
+
+	maxPlaceholder := 0
+	for _, match := range placeholderRegexp.FindAllSubmatch(unquoted, -1) {
+		n, _ := strconv.Atoi(string(match[1]))
+		if n > maxPlaceholder+1 {
+			return fmt.Errorf("psql: placeholder $%d appears before $%d", n, maxPlaceholder+1)
+		}
+		if n > maxPlaceholder {
+			maxPlaceholder = n
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSyntax(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr bool
+	}{
+		{
+			name:  "valid query",
+			query: "SELECT id, name FROM users WHERE (name = $1 OR name = $2) AND code IN ('(', 'O''Brien') AND age > $1",
+		},
+		{
+			name:  "valid insert",
+			query: BuildSQLInsert("cashboxes", []string{"responsable", "country"}),
+		},
+		{
+			name:    "unclosed parenthesis",
+			query:   "SELECT id FROM users WHERE (name = $1",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected parenthesis",
+			query:   "SELECT id FROM users WHERE name = $1)",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed quote",
+			query:   "SELECT id FROM users WHERE name = 'Alejandro",
+			wantErr: true,
+		},
+		{
+			name:    "trailing comma before FROM",
+			query:   "SELECT id, name, FROM users",
+			wantErr: true,
+		},
+		{
+			name:    "trailing comma before parenthesis",
+			query:   "INSERT INTO users (name, email, ) VALUES ($1, $2)",
+			wantErr: true,
+		},
+		{
+			name:    "placeholders out of order",
+			query:   "SELECT id FROM users WHERE name = $2 AND age = $1",
+			wantErr: true,
+		},
+		{
+			name:    "missing placeholder",
+			query:   "SELECT id FROM users WHERE name = $1 AND age = $3",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSyntax(tt.query)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
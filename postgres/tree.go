@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"fmt"
+)
+
+// BuildSQLRecursiveTree builds a recursive query of postgres that walks the rows of the table
+// from the rootID down through its children linked by the parentCol, and its arguments, ej:
+// WITH RECURSIVE tree AS (SELECT categories.* FROM categories WHERE categories.id = $1
+// UNION ALL SELECT child.* FROM categories child INNER JOIN tree ON child.parent_id = tree.id)
+// SELECT * FROM tree
+func BuildSQLRecursiveTree(table, idCol, parentCol string, rootID interface{}) (string, []interface{}) {
+	for _, identifier := range []string{table, idCol, parentCol} {
+		if !isValidIdentifier(identifier) {
+			return ErrInvalidIdentifier, nil
+		}
+	}
+
+	p := &params{}
+	query := fmt.Sprintf("WITH RECURSIVE tree AS (SELECT %s.* FROM %s WHERE %s.%s = %s "+
+		"UNION ALL SELECT child.* FROM %s child INNER JOIN tree ON child.%s = tree.%s) SELECT * FROM tree",
+		table, table, table, idCol, p.bind(rootID),
+		table, parentCol, idCol,
+	)
+
+	return query, p.args
+}
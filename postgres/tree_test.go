@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSQLRecursiveTree(t *testing.T) {
+	tests := []struct {
+		name      string
+		table     string
+		idCol     string
+		parentCol string
+		rootID    interface{}
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "categories tree",
+			table:     "categories",
+			idCol:     "id",
+			parentCol: "parent_id",
+			rootID:    7,
+			wantQuery: "WITH RECURSIVE tree AS (SELECT categories.* FROM categories WHERE categories.id = $1 " +
+				"UNION ALL SELECT child.* FROM categories child INNER JOIN tree ON child.parent_id = tree.id) SELECT * FROM tree",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:      "org chart of a schema",
+			table:     "hr.employees",
+			idCol:     "employee_id",
+			parentCol: "manager_id",
+			rootID:    "ceo",
+			wantQuery: "WITH RECURSIVE tree AS (SELECT hr.employees.* FROM hr.employees WHERE hr.employees.employee_id = $1 " +
+				"UNION ALL SELECT child.* FROM hr.employees child INNER JOIN tree ON child.manager_id = tree.employee_id) SELECT * FROM tree",
+			wantArgs: []interface{}{"ceo"},
+		},
+		{
+			name:      "invalid parent column",
+			table:     "categories",
+			idCol:     "id",
+			parentCol: "parent_id; DROP TABLE categories",
+			rootID:    7,
+			wantQuery: ErrInvalidIdentifier,
+			wantArgs:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQuery, gotArgs := BuildSQLRecursiveTree(tt.table, tt.idCol, tt.parentCol, tt.rootID)
+			assert.Equal(t, tt.wantQuery, gotQuery)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrConflictColumnsAreEmpty is returned when an upsert doesn't have the columns of the conflict
+const ErrConflictColumnsAreEmpty = "FAILED! YOU NEED TO SEND CONFLICT COLUMNS"
+
+// UpsertInsertedColumn is the column to add in the RETURNING of an upsert
+// (INSERT ... ON CONFLICT DO UPDATE) to know if the row was inserted or updated:
+// xmax is 0 only for the rows inserted by the statement, ej:
+// INSERT INTO users (email) VALUES ($1) ON CONFLICT (email) DO UPDATE SET email = EXCLUDED.email
+// RETURNING id, (xmax = 0) AS inserted
+const UpsertInsertedColumn = "(xmax = 0) AS inserted"
+
+// ParseUpsertInserted returns if the upsert inserted the row, using the value
+// scanned from UpsertInsertedColumn. It supports the formats returned by the drivers:
+// bool, and the text "t", "f", "true" or "false" as string or []byte
+func ParseUpsertInserted(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case []byte:
+		return parseUpsertInsertedText(string(v))
+	case string:
+		return parseUpsertInsertedText(v)
+	default:
+		return false, fmt.Errorf("psql: could not parse the upsert inserted value %v of type %T", value, value)
+	}
+}
+
+func parseUpsertInsertedText(value string) (bool, error) {
+	inserted, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("psql: could not parse the upsert inserted value %q", value)
+	}
+
+	return inserted, nil
+}
+
+// BuildSQLInsertIgnore builds a query INSERT of postgres that does nothing when the row
+// conflicts with the conflictColumns, if conflictColumns is empty it ignores any conflict.
+// The RETURNING returns no rows when the insert is skipped by the conflict
+func BuildSQLInsertIgnore(table string, fields []string, conflictColumns []string) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
+	}
+
+	conflict := "ON CONFLICT"
+	if len(conflictColumns) > 0 {
+		conflict = fmt.Sprintf("ON CONFLICT (%s)", strings.Join(conflictColumns, ", "))
+	}
+
+	return fmt.Sprintf("%s %s DO NOTHING RETURNING %s",
+		buildSQLInsertValues(table, fields), conflict, strings.Join(defaultReturning(), ", "))
+}
+
+// BuildSQLUpsert builds a query INSERT of postgres that updates the updateColumns with
+// the inserted values (EXCLUDED) when the row conflicts with the conflictColumns,
+// if updateColumns is empty it does nothing on conflict
+func BuildSQLUpsert(table string, fields []string, conflictColumns []string, updateColumns []string) string {
+	return BuildSQLUpsertReturning(table, fields, conflictColumns, updateColumns, nil)
+}
+
+// BuildSQLUpsertReturning builds the same query of BuildSQLUpsert with the returning columns,
+// if returning is empty it returns `id, created_at`.
+// To diagnose the conflicts of a bulk upsert return the conflict columns and UpsertInsertedColumn,
+// ej: RETURNING id, product_id, country, (xmax = 0) AS inserted
+// the rows with inserted false conflicted by that key, and with DO NOTHING the rows
+// of the input missing in the result are the ones that conflicted
+func BuildSQLUpsertReturning(table string, fields []string, conflictColumns []string, updateColumns []string, returning []string) string {
+	updates := make([]UpsertUpdate, 0, len(updateColumns))
+	for _, column := range updateColumns {
+		updates = append(updates, UpsertUpdate{Column: column})
+	}
+
+	return BuildSQLUpsertExpressions(table, fields, conflictColumns, updates, returning)
+}
+
+// UpsertUpdate is the update of a column when an upsert conflicts, the Expression
+// can use the current row (table.column) and the inserted row (EXCLUDED.column),
+// ej: {Column: "count", Expression: "metrics.count + EXCLUDED.count"}.
+// If the Expression is empty the column is updated with EXCLUDED.column
+type UpsertUpdate struct {
+	Column     string
+	Expression string
+}
+
+// BuildSQLUpsertExpressions builds a query INSERT of postgres that updates the columns with
+// their expressions when the row conflicts with the conflictColumns, if updates is empty
+// it does nothing on conflict, and if returning is empty it returns `id, created_at`
+func BuildSQLUpsertExpressions(table string, fields []string, conflictColumns []string, updates []UpsertUpdate, returning []string) string {
+	if len(fields) == 0 {
+		return ErrFieldsAreEmpty
+	}
+	if len(conflictColumns) == 0 {
+		return ErrConflictColumnsAreEmpty
+	}
+	if len(returning) == 0 {
+		returning = defaultReturning()
+	}
+
+	action := "DO NOTHING"
+	if len(updates) > 0 {
+		set := make([]string, 0, len(updates))
+		for _, update := range updates {
+			expression := update.Expression
+			if expression == "" {
+				expression = "EXCLUDED." + update.Column
+			}
+			set = append(set, fmt.Sprintf("%s = %s", update.Column, expression))
+		}
+		action = "DO UPDATE SET " + strings.Join(set, ", ")
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) %s RETURNING %s",
+		buildSQLInsertValues(table, fields),
+		strings.Join(conflictColumns, ", "),
+		action,
+		strings.Join(returning, ", "),
+	)
+}
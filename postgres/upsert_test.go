@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUpsertInserted(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    bool
+		wantErr bool
+	}{
+		{name: "bool inserted", value: true, want: true},
+		{name: "bool updated", value: false, want: false},
+		{name: "bytes inserted", value: []byte("t"), want: true},
+		{name: "bytes updated", value: []byte("f"), want: false},
+		{name: "string inserted", value: "true", want: true},
+		{name: "invalid text", value: "maybe", wantErr: true},
+		{name: "nil value", value: nil, wantErr: true},
+		{name: "invalid type", value: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUpsertInserted(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildSQLInsertIgnore(t *testing.T) {
+	tableTest := []struct {
+		name            string
+		table           string
+		fields          []string
+		conflictColumns []string
+		want            string
+	}{
+		{
+			name:            "with conflict columns",
+			table:           "users",
+			fields:          []string{"email", "name"},
+			conflictColumns: []string{"email"},
+			want:            "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT (email) DO NOTHING RETURNING id, created_at",
+		},
+		{
+			name:            "without conflict columns",
+			table:           "users",
+			fields:          []string{"email", "name"},
+			conflictColumns: nil,
+			want:            "INSERT INTO users (email, name) VALUES ($1, $2) ON CONFLICT DO NOTHING RETURNING id, created_at",
+		},
+		{
+			name:            "empty fields",
+			table:           "users",
+			fields:          []string{},
+			conflictColumns: []string{"email"},
+			want:            ErrFieldsAreEmpty,
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLInsertIgnore(tt.table, tt.fields, tt.conflictColumns), tt.name)
+	}
+}
+
+func TestBuildSQLUpsert(t *testing.T) {
+	tableTest := []struct {
+		name            string
+		table           string
+		fields          []string
+		conflictColumns []string
+		updateColumns   []string
+		want            string
+	}{
+		{
+			name:            "update on conflict",
+			table:           "prices",
+			fields:          []string{"product_id", "country", "amount", "currency"},
+			conflictColumns: []string{"product_id", "country"},
+			updateColumns:   []string{"amount", "currency"},
+			want:            "INSERT INTO prices (product_id, country, amount, currency) VALUES ($1, $2, $3, $4) ON CONFLICT (product_id, country) DO UPDATE SET amount = EXCLUDED.amount, currency = EXCLUDED.currency RETURNING id, created_at",
+		},
+		{
+			name:            "nothing on conflict",
+			table:           "prices",
+			fields:          []string{"product_id", "amount"},
+			conflictColumns: []string{"product_id"},
+			updateColumns:   nil,
+			want:            "INSERT INTO prices (product_id, amount) VALUES ($1, $2) ON CONFLICT (product_id) DO NOTHING RETURNING id, created_at",
+		},
+		{
+			name:            "empty conflict columns",
+			table:           "prices",
+			fields:          []string{"product_id", "amount"},
+			conflictColumns: []string{},
+			updateColumns:   []string{"amount"},
+			want:            ErrConflictColumnsAreEmpty,
+		},
+		{
+			name:            "empty fields",
+			table:           "prices",
+			fields:          []string{},
+			conflictColumns: []string{"product_id"},
+			updateColumns:   []string{"amount"},
+			want:            ErrFieldsAreEmpty,
+		},
+	}
+
+	for _, tt := range tableTest {
+		assert.Equal(t, tt.want, BuildSQLUpsert(tt.table, tt.fields, tt.conflictColumns, tt.updateColumns), tt.name)
+	}
+}
+
+func TestBuildSQLUpsertReturning(t *testing.T) {
+	tableTest := []struct {
+		name      string
+		returning []string
+		want      string
+	}{
+		{
+			name:      "returning the conflict diagnostics",
+			returning: []string{"id", "product_id", "country", UpsertInsertedColumn},
+			want:      "INSERT INTO prices (product_id, country, amount) VALUES ($1, $2, $3) ON CONFLICT (product_id, country) DO UPDATE SET amount = EXCLUDED.amount RETURNING id, product_id, country, (xmax = 0) AS inserted",
+		},
+		{
+			name:      "default returning",
+			returning: nil,
+			want:      "INSERT INTO prices (product_id, country, amount) VALUES ($1, $2, $3) ON CONFLICT (product_id, country) DO UPDATE SET amount = EXCLUDED.amount RETURNING id, created_at",
+		},
+	}
+
+	for _, tt := range tableTest {
+		got := BuildSQLUpsertReturning("prices", []string{"product_id", "country", "amount"}, []string{"product_id", "country"}, []string{"amount"}, tt.returning)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestBuildSQLUpsertExpressions(t *testing.T) {
+	tableTest := []struct {
+		name    string
+		updates []UpsertUpdate
+		want    string
+	}{
+		{
+			name: "increment a counter",
+			updates: []UpsertUpdate{
+				{Column: "count", Expression: "metrics.count + EXCLUDED.count"},
+				{Column: "last_seen_at"},
+			},
+			want: "INSERT INTO metrics (key, count, last_seen_at) VALUES ($1, $2, $3) ON CONFLICT (key) DO UPDATE SET count = metrics.count + EXCLUDED.count, last_seen_at = EXCLUDED.last_seen_at RETURNING id, created_at",
+		},
+		{
+			name:    "nothing on conflict",
+			updates: nil,
+			want:    "INSERT INTO metrics (key, count, last_seen_at) VALUES ($1, $2, $3) ON CONFLICT (key) DO NOTHING RETURNING id, created_at",
+		},
+	}
+
+	for _, tt := range tableTest {
+		got := BuildSQLUpsertExpressions("metrics", []string{"key", "count", "last_seen_at"}, []string{"key"}, tt.updates, nil)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}